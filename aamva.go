@@ -0,0 +1,25 @@
+package zbar
+
+import (
+	"fmt"
+
+	"github.com/zooyer/zbar/aamva"
+)
+
+/*------------------------------------------------------------*/
+/** @name Driver licences
+ * see package aamva
+ */
+/*@{*/
+
+/** parse the AAMVA driver licence or ID card data of a PDF417
+ * symbol.
+ */
+func (sym Symbol) License() (*aamva.License, error) {
+	if sym.Type&ZBAR_SYMBOL != ZBAR_PDF417 {
+		return nil, fmt.Errorf("zbar: %v is not a PDF417 symbol", sym.Type)
+	}
+	return aamva.Parse(sym.Data)
+}
+
+/*@}*/
@@ -0,0 +1,177 @@
+/** Package aamva parses the PDF417 barcode on US and Canadian driver
+ * licences and identification cards (AAMVA DL/ID card design
+ * standard, versions 1 to 10).
+ *
+ * the barcode holds a header, a directory of subfiles and the
+ * subfiles themselves: "DL" or "ID" with the standard data elements
+ * and optional "Z?" jurisdiction specific subfiles.  each data element
+ * is a 3 letter ID followed by its value, eg "DCSPUBLIC".  Parse()
+ * returns the raw elements and a typed License record.
+ */
+package aamva
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/** invalid barcode data. */
+type ParseError struct {
+	Offset  int    // byte offset into the data, -1 for element values
+	Element string // offending data element, if any
+	Msg     string
+}
+
+func (e *ParseError) Error() string {
+	switch {
+	case e.Element != "":
+		return fmt.Sprintf("aamva: %s: %s", e.Element, e.Msg)
+	case e.Offset >= 0:
+		return fmt.Sprintf("aamva: offset %d: %s", e.Offset, e.Msg)
+	}
+	return "aamva: " + e.Msg
+}
+
+/** barcode header. */
+type Header struct {
+	IIN                 string // issuer identification number
+	Version             int    // AAMVA version number, 1 to 10
+	JurisdictionVersion int    // 0 for version 1
+	Entries             int    // number of subfiles
+}
+
+/** subfile with its data elements in order. */
+type Subfile struct {
+	Type     string // "DL", "ID" or "Z?"
+	Offset   int
+	Elements []Element
+}
+
+/** data element. */
+type Element struct {
+	ID    string // eg "DCS"
+	Value string
+}
+
+/** value of the element id, "" if absent. */
+func (s *Subfile) Get(id string) string {
+	for _, e := range s.Elements {
+		if e.ID == id {
+			return e.Value
+		}
+	}
+	return ""
+}
+
+/** parse the header and subfile directory and split the subfiles
+ * into data elements.
+ * subfile offsets are not trusted blindly: when the directory points
+ * elsewhere the subfile type is searched for instead, as some issuers
+ * get the offsets wrong.
+ */
+func ParseSubfiles(data string) (*Header, []Subfile, error) {
+	if len(data) < 4 || data[0] != '@' {
+		return nil, nil, &ParseError{0, "", "missing compliance indicator '@'"}
+	}
+	var separators = data[1:4] // element separator, record separator, segment terminator
+
+	var pos = 4
+	var file = data[pos:min(pos+5, len(data))]
+	if file != "ANSI " && file != "AAMVA" {
+		return nil, nil, &ParseError{pos, "", fmt.Sprintf("invalid file type %q", file)}
+	}
+	pos += 5
+
+	var h = &Header{}
+	var field = func(n int, what string) (string, error) {
+		if pos+n > len(data) || !isDigits(data[pos:pos+n]) {
+			return "", &ParseError{pos, "", "invalid " + what}
+		}
+		pos += n
+		return data[pos-n : pos], nil
+	}
+	var err error
+	var s string
+	if h.IIN, err = field(6, "issuer identification number"); err != nil {
+		return nil, nil, err
+	}
+	if s, err = field(2, "version"); err != nil {
+		return nil, nil, err
+	}
+	if h.Version, _ = strconv.Atoi(s); h.Version < 1 || h.Version > 10 {
+		return nil, nil, &ParseError{pos - 2, "", fmt.Sprintf("unsupported version %d", h.Version)}
+	}
+	if h.Version >= 2 {
+		if s, err = field(2, "jurisdiction version"); err != nil {
+			return nil, nil, err
+		}
+		h.JurisdictionVersion, _ = strconv.Atoi(s)
+	}
+	if s, err = field(2, "number of entries"); err != nil {
+		return nil, nil, err
+	}
+	if h.Entries, _ = strconv.Atoi(s); h.Entries == 0 {
+		return nil, nil, &ParseError{pos - 2, "", "no subfiles"}
+	}
+
+	var subfiles = make([]Subfile, 0, h.Entries)
+	var end = pos + 10*h.Entries
+	if end > len(data) {
+		return nil, nil, &ParseError{pos, "", "truncated subfile directory"}
+	}
+	for i := 0; i < h.Entries; i++ {
+		var entry = data[pos : pos+10]
+		if !isDigits(entry[2:]) {
+			return nil, nil, &ParseError{pos, "", fmt.Sprintf("invalid directory entry %q", entry)}
+		}
+		var typ = entry[:2]
+		var offset, _ = strconv.Atoi(entry[2:6])
+		var length, _ = strconv.Atoi(entry[6:])
+		if length < 2 || offset+length > len(data) {
+			return nil, nil, &ParseError{pos, "", fmt.Sprintf("subfile %s out of bounds: offset %d, length %d", typ, offset, length)}
+		}
+		pos += 10
+
+		if data[offset:offset+2] != typ {
+			var found = strings.Index(data[end:], typ)
+			if found < 0 {
+				return nil, nil, &ParseError{pos - 10, "", "subfile " + typ + " not found"}
+			}
+			if offset = end + found; offset+length > len(data) {
+				return nil, nil, &ParseError{pos - 10, "", fmt.Sprintf("subfile %s out of bounds: offset %d, length %d", typ, offset, length)}
+			}
+		}
+		var body = data[offset+2 : offset+length]
+		subfiles = append(subfiles, Subfile{Type: typ, Offset: offset, Elements: elements(body, separators)})
+	}
+	return h, subfiles, nil
+}
+
+/** split a subfile body into elements.
+ * the declared separators are honored, with LF and CR accepted too.
+ */
+func elements(body, separators string) []Element {
+	if i := strings.IndexByte(body, separators[2]); i >= 0 {
+		body = body[:i]
+	}
+	var list []Element
+	for _, line := range strings.FieldsFunc(body, func(r rune) bool {
+		return r == '\n' || r == '\r' || r == rune(separators[0]) || r == rune(separators[1])
+	}) {
+		if len(line) < 3 {
+			continue
+		}
+		list = append(list, Element{ID: line[:3], Value: strings.TrimSpace(line[3:])})
+	}
+	return list
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,72 @@
+package aamva
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+/** assemble a barcode from subfile bodies ("DLDAQ...\n...") with a
+ * correct directory; jver < 0 omits the jurisdiction version.
+ */
+func build(iin string, version, jver int, subfiles ...string) string {
+	var header = fmt.Sprintf("@\n\x1e\rANSI %s%02d", iin, version)
+	if jver >= 0 {
+		header += fmt.Sprintf("%02d", jver)
+	}
+	header += fmt.Sprintf("%02d", len(subfiles))
+
+	var offset = len(header) + 10*len(subfiles)
+	var dir, body strings.Builder
+	for _, s := range subfiles {
+		s += "\r"
+		fmt.Fprintf(&dir, "%s%04d%04d", s[:2], offset, len(s))
+		body.WriteString(s)
+		offset += len(s)
+	}
+	return header + dir.String() + body.String()
+}
+
+func TestParseSubfiles(t *testing.T) {
+	var data = build("636014", 8, 1, "DLDAQD1234562\nDCSPUBLIC\nDAK902230000  ", "ZCZCAY\nZCBCORR LENS")
+	h, subfiles, err := ParseSubfiles(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.IIN != "636014" || h.Version != 8 || h.JurisdictionVersion != 1 || h.Entries != 2 {
+		t.Errorf("header %+v", h)
+	}
+	if len(subfiles) != 2 || subfiles[0].Get("DAQ") != "D1234562" || subfiles[0].Get("DAK") != "902230000" ||
+		subfiles[1].Type != "ZC" || subfiles[1].Get("ZCB") != "CORR LENS" {
+		t.Errorf("subfiles %+v", subfiles)
+	}
+
+	// wrong offsets are recovered by searching for the subfile type
+	var broken = strings.Replace(data, "DL0041", "DL0040", 1)
+	if _, subfiles, err = ParseSubfiles(broken); err != nil || subfiles[0].Get("DCS") != "PUBLIC" {
+		t.Errorf("broken offset: %+v %v", subfiles, err)
+	}
+
+	// version 1 has no jurisdiction version
+	h, subfiles, err = ParseSubfiles(build("636001", 1, -1, "DLDAQ123"))
+	if err != nil || h.Version != 1 || h.Entries != 1 || subfiles[0].Get("DAQ") != "123" {
+		t.Errorf("version 1: %+v %+v %v", h, subfiles, err)
+	}
+
+	for data, offset := range map[string]int{
+		"":                               0,
+		"@\n\x1e\rFOO  636014080101":     4,
+		"@\n\x1e\rANSI 63601X080101":     9,
+		"@\n\x1e\rANSI 636014110101":     15,
+		"@\n\x1e\rANSI 636014080100":     19,
+		"@\n\x1e\rANSI 636014080101DL00": 21,
+		"@\n\x1e\rANSI 636014080102DL00410000ZC00500020DLDAQX\n": 21,
+		"@\n\x1e\rANSI 636014080101DL-0010010DLDAQX\n":           21,
+		"@\n\x1e\rANSI 636014080101DL00310099DLDAQX\n":           21,
+	} {
+		_, _, err := ParseSubfiles(data)
+		if e, ok := err.(*ParseError); !ok || e.Offset != offset {
+			t.Errorf("%q: %v, want error at %d", data, err, offset)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package aamva
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+/** sex as printed on the card. */
+type Sex string
+
+const (
+	SexUnknown Sex = ""
+	SexMale    Sex = "M"
+	SexFemale  Sex = "F"
+	SexX       Sex = "X" /**< not specified (version 10) */
+)
+
+/** driver licence or identification card record. */
+type License struct {
+	Header
+	Jurisdiction Jurisdiction // from the IIN; Code falls back to the address state
+	DocumentType string       // "DL" or "ID"
+
+	Number                string // DAQ customer ID number
+	DocumentDiscriminator string // DCF
+	FamilyName            string
+	GivenName             string
+	MiddleName            string
+	Suffix                string
+	DateOfBirth           time.Time
+	IssueDate             time.Time
+	Expiry                time.Time
+	Sex                   Sex
+	EyeColor              string
+	HairColor             string
+	HeightCM              int // 0 if not given
+	Street, Street2       string
+	City, State           string
+	PostalCode            string
+	Country               string // DCG, "USA" or "CAN"
+	Class                 string
+	Restrictions          string
+	Endorsements          string
+	Compliance            string // DDA: "F" fully compliant, "N" non-compliant
+	OrganDonor            bool
+	Veteran               bool
+
+	Subfiles []Subfile
+}
+
+/** parse an AAMVA barcode.
+ * dates are MMDDCCYY for US and CCYYMMDD for Canadian issuers and for
+ * version 1 cards; a date that does not parse in the expected order
+ * is tried in the other one, since not all issuers follow the
+ * convention.
+ */
+func Parse(data string) (*License, error) {
+	h, subfiles, err := ParseSubfiles(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var l = &License{Header: *h, Subfiles: subfiles}
+	var main *Subfile
+	for i := range subfiles {
+		if subfiles[i].Type == "DL" || subfiles[i].Type == "ID" {
+			main = &subfiles[i]
+			break
+		}
+	}
+	if main == nil {
+		return nil, &ParseError{-1, "", "no DL or ID subfile"}
+	}
+	l.DocumentType = main.Type
+	var get = main.Get
+
+	l.Jurisdiction, _ = LookupIIN(h.IIN)
+	l.Number = get("DAQ")
+	l.DocumentDiscriminator = get("DCF")
+	if l.Number == "" {
+		return nil, &ParseError{-1, "DAQ", "missing customer ID number"}
+	}
+
+	l.names(get)
+
+	l.Street, l.Street2 = get("DAG"), get("DAH")
+	l.City, l.State = get("DAI"), get("DAJ")
+	l.PostalCode = strings.TrimRight(get("DAK"), " ")
+	if l.Jurisdiction.Code == "" {
+		l.Jurisdiction.Code = l.State
+	}
+	if l.Country = get("DCG"); l.Country == "" {
+		l.Country = l.Jurisdiction.Country
+	}
+
+	l.Class = first(get("DCA"), get("DAR"))
+	l.Restrictions = first(get("DCB"), get("DAS"))
+	l.Endorsements = first(get("DCD"), get("DAT"))
+	l.Compliance = get("DDA")
+	l.OrganDonor = get("DDK") == "1"
+	l.Veteran = get("DDL") == "1"
+	l.EyeColor, l.HairColor = get("DAY"), get("DAZ")
+
+	switch get("DBC") {
+	case "1", "M":
+		l.Sex = SexMale
+	case "2", "F":
+		l.Sex = SexFemale
+	case "9", "X":
+		l.Sex = SexX
+	}
+
+	var ymd = h.Version == 1 || l.Country == "CAN"
+	for _, d := range []struct {
+		id  string
+		dst *time.Time
+	}{{"DBB", &l.DateOfBirth}, {"DBD", &l.IssueDate}, {"DBA", &l.Expiry}} {
+		var value = get(d.id)
+		if value == "" {
+			continue
+		}
+		if *d.dst, err = parseDate(value, ymd); err != nil {
+			return nil, &ParseError{-1, d.id, "invalid date " + strconv.Quote(value)}
+		}
+	}
+	if l.DateOfBirth.IsZero() {
+		return nil, &ParseError{-1, "DBB", "missing date of birth"}
+	}
+
+	if l.HeightCM, err = height(get("DAU"), get("DAV"), h.Version); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+/** names moved between elements over the versions:
+ * version 1 has DAA (full name) or DAB/DAC/DAD/DAE, versions 2 and 3
+ * DCS and DCT (given names), later versions DCS/DAC/DAD/DCU.
+ */
+func (l *License) names(get func(string) string) {
+	l.FamilyName = first(get("DCS"), get("DAB"))
+	l.GivenName = get("DAC")
+	l.MiddleName = get("DAD")
+	l.Suffix = first(get("DCU"), get("DAE"))
+
+	if l.GivenName == "" {
+		if given := get("DCT"); given != "" {
+			l.GivenName, l.MiddleName = cutName(given, l.MiddleName)
+		}
+	}
+	if full := get("DAA"); full != "" && l.FamilyName == "" {
+		var parts = strings.Split(full, ",")
+		if len(parts) == 1 {
+			parts = strings.Fields(full)
+		}
+		l.FamilyName = strings.TrimSpace(parts[0])
+		if len(parts) > 1 && l.GivenName == "" {
+			l.GivenName = strings.TrimSpace(parts[1])
+		}
+		if len(parts) > 2 && l.MiddleName == "" {
+			l.MiddleName = strings.TrimSpace(strings.Join(parts[2:], " "))
+		}
+	}
+}
+
+/** split "FIRST,MIDDLE" or "FIRST MIDDLE". */
+func cutName(given, middle string) (string, string) {
+	var sep = ","
+	if !strings.Contains(given, sep) {
+		sep = " "
+	}
+	if f, m, ok := strings.Cut(given, sep); ok {
+		return strings.TrimSpace(f), first(middle, strings.TrimSpace(m))
+	}
+	return given, middle
+}
+
+/** MMDDCCYY or CCYYMMDD. */
+func parseDate(value string, ymd bool) (time.Time, error) {
+	var layouts = [2]string{"01022006", "20060102"}
+	if ymd {
+		layouts[0], layouts[1] = layouts[1], layouts[0]
+	}
+	t, err := time.Parse(layouts[0], value)
+	if err != nil {
+		t, err = time.Parse(layouts[1], value)
+	}
+	return t, err
+}
+
+/** height in centimetres: "070 IN" or "178 CM" since version 2,
+ * feet and inches ("509") or DAV centimetres in version 1.
+ */
+func height(dau, dav string, version int) (int, error) {
+	if dau == "" && dav != "" {
+		dau = dav + " CM"
+	}
+	if dau == "" {
+		return 0, nil
+	}
+	var s = strings.ToUpper(strings.ReplaceAll(dau, " ", ""))
+	var unit string
+	if strings.HasSuffix(s, "IN") || strings.HasSuffix(s, "CM") {
+		s, unit = s[:len(s)-2], s[len(s)-2:]
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, &ParseError{-1, "DAU", "invalid height " + strconv.Quote(dau)}
+	}
+	switch {
+	case unit == "CM":
+		return n, nil
+	case unit == "IN":
+		return int(float64(n)*2.54 + 0.5), nil
+	case version == 1 && len(s) == 3:
+		return int(float64(n/100*12+n%100)*2.54 + 0.5), nil
+	}
+	return 0, &ParseError{-1, "DAU", "invalid height " + strconv.Quote(dau)}
+}
+
+/** age in whole years at t. */
+func (l *License) Age(t time.Time) int {
+	var age = t.Year() - l.DateOfBirth.Year()
+	if t.Month() < l.DateOfBirth.Month() || t.Month() == l.DateOfBirth.Month() && t.Day() < l.DateOfBirth.Day() {
+		age--
+	}
+	return age
+}
+
+/** check whether the document is expired at t. */
+func (l *License) Expired(t time.Time) bool {
+	return !l.Expiry.IsZero() && !t.Before(l.Expiry.AddDate(0, 0, 1))
+}
+
+func first(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,81 @@
+package aamva
+
+import (
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseV8(t *testing.T) {
+	var data = build("636014", 8, 1,
+		"DLDAQD1234562\nDCSPUBLIC\nDDEN\nDACJOHN\nDDFN\nDADQUINCY\nDDGN\nDCAC\nDCBNONE\nDCDNONE\n"+
+			"DBD08312013\nDBB08311977\nDBA08312018\nDBC1\nDAU069 IN\nDAYBRO\nDAG789 E OAK ST\n"+
+			"DAIANYTOWN\nDAJCA\nDAK902230000  \nDCF83D9BN217QO983B1\nDCGUSA\nDAZBRO\nDDAF\nDDK1",
+		"ZCZCAY")
+	l, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Jurisdiction.Code != "CA" || l.DocumentType != "DL" || l.Number != "D1234562" ||
+		l.FamilyName != "PUBLIC" || l.GivenName != "JOHN" || l.MiddleName != "QUINCY" ||
+		!l.DateOfBirth.Equal(date(1977, 8, 31)) || !l.IssueDate.Equal(date(2013, 8, 31)) ||
+		!l.Expiry.Equal(date(2018, 8, 31)) || l.Sex != SexMale || l.HeightCM != 175 ||
+		l.City != "ANYTOWN" || l.PostalCode != "902230000" || l.Class != "C" ||
+		l.Compliance != "F" || !l.OrganDonor || l.Veteran || len(l.Subfiles) != 2 {
+		t.Errorf("got %+v", l)
+	}
+
+	if age := l.Age(date(2017, 8, 30)); age != 39 {
+		t.Errorf("age = %d", age)
+	}
+	if age := l.Age(date(2017, 8, 31)); age != 40 {
+		t.Errorf("age = %d", age)
+	}
+	if l.Expired(date(2018, 8, 31)) || !l.Expired(date(2018, 9, 1)) {
+		t.Error("expiry")
+	}
+}
+
+func TestParseCanada(t *testing.T) {
+	// Ontario: CCYYMMDD dates, given names in DCT (version 3)
+	l, err := Parse(build("636012", 3, 0,
+		"DLDAQP1234-56789-01234\nDCSSMITH\nDCTJANE,MARY\nDBB19850214\nDBA20250214\nDBC2\nDAU165 CM\nDCGCAN"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Jurisdiction.Country != "CAN" || l.GivenName != "JANE" || l.MiddleName != "MARY" ||
+		!l.DateOfBirth.Equal(date(1985, 2, 14)) || !l.Expiry.Equal(date(2025, 2, 14)) ||
+		l.Sex != SexFemale || l.HeightCM != 165 {
+		t.Errorf("got %+v", l)
+	}
+}
+
+func TestParseV1(t *testing.T) {
+	l, err := Parse(build("636001", 1, -1,
+		"DLDAQ123456789\nDAADOE,JOHN,Q\nDBB19700102\nDBCM\nDAU511\nDARD"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.FamilyName != "DOE" || l.GivenName != "JOHN" || l.MiddleName != "Q" ||
+		!l.DateOfBirth.Equal(date(1970, 1, 2)) || l.Sex != SexMale || l.HeightCM != 180 || l.Class != "D" {
+		t.Errorf("got %+v", l)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for data, element := range map[string]string{
+		build("636001", 9, 0, "DLDCSX\nDBB01011990"):          "DAQ",
+		build("636001", 9, 0, "DLDAQ1\nDCSX"):                 "DBB",
+		build("636001", 9, 0, "DLDAQ1\nDBB13351990"):          "DBB",
+		build("636001", 9, 0, "DLDAQ1\nDBB01011990\nDAUtall"): "DAU",
+		build("636001", 10, 0, "ZNZNAX"):                      "",
+	} {
+		_, err := Parse(data)
+		if e, ok := err.(*ParseError); !ok || e.Element != element {
+			t.Errorf("%q: %v, want error on %q", data, err, element)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package zbar
+
+import (
+	"errors"
+
+	"github.com/zooyer/zbar/gtin"
+)
+
+/*------------------------------------------------------------*/
+/** @name EAN/UPC add-ons
+ * libzbar 0.10 appends add-on digits to the data of the main symbol
+ * and flags the type with ZBAR_ADDON2 or ZBAR_ADDON5; newer libraries
+ * report a ZBAR_COMPOSITE symbol with the main symbol and a ZBAR_EAN2
+ * or ZBAR_EAN5 component.  either way the result is split into the
+ * Main and AddOn fields of Symbol
+ */
+/*@{*/
+
+/** returned for symbols without the requested add-on. */
+var ErrNoAddOn = errors.New("zbar: symbol has no such add-on")
+
+/** set the Main, AddOn and AddOnType fields of a symbol. */
+func splitAddOn(symbol *Symbol, sym *ZBarSymbol) {
+	symbol.Main = symbol.Data
+
+	switch addon := symbol.Type & ZBAR_ADDON; {
+	case addon == ZBAR_ADDON2 || addon == ZBAR_ADDON5:
+		var n = 2
+		if addon == ZBAR_ADDON5 {
+			n = 5
+		}
+		if len(symbol.Data) > n {
+			symbol.Main, symbol.AddOn = symbol.Data[:len(symbol.Data)-n], symbol.Data[len(symbol.Data)-n:]
+			symbol.AddOnType = addon
+		}
+
+	case symbol.Type == ZBAR_EAN2 || symbol.Type == ZBAR_EAN5:
+		symbol.Main, symbol.AddOn, symbol.AddOnType = "", symbol.Data, symbol.Type
+
+	case symbol.Type == ZBAR_COMPOSITE && sym != nil:
+		var components = ZBarSymbolGetComponents(sym)
+		if components == nil {
+			break
+		}
+		symbol.Main = ""
+		for c := ZBarSymbolSetFirstSymbol(components); c != nil; c = ZBarSymbolNext(c) {
+			switch t := ZBarSymbolGetType(c); t {
+			case ZBAR_EAN2, ZBAR_EAN5:
+				symbol.AddOn, symbol.AddOnType = symbolData(c), t
+			default:
+				symbol.Main += symbolData(c)
+			}
+		}
+	}
+}
+
+/** data of the main symbol, also for symbols not split by the
+ * scanner (eg constructed by hand).
+ */
+func (sym Symbol) mainData() string {
+	if sym.Main == "" && sym.AddOnType == ZBAR_NONE {
+		return sym.Data
+	}
+	return sym.Main
+}
+
+/** length of the add-on of a given type: 2, 5 or 0. */
+func addOnLength(t ZBarSymbolType) int {
+	switch t {
+	case ZBAR_ADDON2, ZBAR_EAN2:
+		return 2
+	case ZBAR_ADDON5, ZBAR_EAN5:
+		return 5
+	}
+	return 0
+}
+
+/** suggested retail price from a 5 digit book add-on. */
+func (sym Symbol) Price() (gtin.Price, error) {
+	if addOnLength(sym.AddOnType) != 5 {
+		return gtin.Price{}, ErrNoAddOn
+	}
+	return gtin.ParsePrice(sym.AddOn)
+}
+
+/** issue number from a 2 digit periodical add-on. */
+func (sym Symbol) Issue() (int, error) {
+	if addOnLength(sym.AddOnType) != 2 {
+		return 0, ErrNoAddOn
+	}
+	return gtin.ParseIssue(sym.AddOn)
+}
+
+/*@}*/
@@ -0,0 +1,35 @@
+package zbar
+
+import "testing"
+
+func TestSplitAddOn(t *testing.T) {
+	var sym = Symbol{Type: ZBAR_ISBN13 | ZBAR_ADDON5, Data: "978030640615752495"}
+	splitAddOn(&sym, nil)
+	if sym.Main != "9780306406157" || sym.AddOn != "52495" || sym.AddOnType != ZBAR_ADDON5 {
+		t.Fatalf("split = %q %q %v", sym.Main, sym.AddOn, sym.AddOnType)
+	}
+	if p, err := sym.Price(); err != nil || p.String() != "USD 24.95" {
+		t.Errorf("price = %v %v", p, err)
+	}
+	if _, err := sym.Issue(); err != ErrNoAddOn {
+		t.Errorf("issue of 5 digit add-on: %v", err)
+	}
+	if g, err := sym.GTIN(); err != nil || g != "09780306406157" {
+		t.Errorf("gtin = %s %v", g, err)
+	}
+
+	sym = Symbol{Type: ZBAR_EAN2, Data: "07"}
+	splitAddOn(&sym, nil)
+	if n, err := sym.Issue(); err != nil || n != 7 || sym.Main != "" {
+		t.Errorf("issue = %d %v, main %q", n, err, sym.Main)
+	}
+
+	sym = Symbol{Type: ZBAR_EAN13, Data: "4006381333931"}
+	splitAddOn(&sym, nil)
+	if sym.Main != sym.Data || sym.AddOn != "" || sym.AddOnType != ZBAR_NONE {
+		t.Errorf("no add-on: %+v", sym)
+	}
+	if _, err := sym.Price(); err != ErrNoAddOn {
+		t.Errorf("price without add-on: %v", err)
+	}
+}
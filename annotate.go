@@ -0,0 +1,144 @@
+package zbar
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strings"
+	"unicode"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+/*------------------------------------------------------------*/
+/** @name Result annotation
+ * draws scan results onto a copy of the scanned image, a headless
+ * alternative to the ZBarWindow overlay
+ */
+/*@{*/
+
+var (
+	annotateBounds  = color.RGBA{R: 0xff, A: 0xff}       /**< bounding box */
+	annotateOutline = color.RGBA{G: 0xc0, A: 0xff}       /**< location polygon */
+	annotateLabel   = color.RGBA{A: 0xc0}                /**< label background */
+	annotateText    = color.RGBA{0xff, 0xff, 0xff, 0xff} /**< label text */
+	annotateFace    = font.Face(basicfont.Face7x13)      /**< label font */
+	annotateMaxText = 48                                 /**< label length limit */
+)
+
+/** draw the symbols found in img onto a copy of it.
+ * each symbol is marked with its bounding box, its location polygon
+ * and a label of its type name and data.
+ */
+func Annotate(img image.Image, symbols []Symbol) *image.RGBA {
+	var dst = image.NewRGBA(img.Bounds())
+	draw.Draw(dst, dst.Rect, img, dst.Rect.Min, draw.Src)
+
+	for _, sym := range symbols {
+		if len(sym.Points) == 0 {
+			continue
+		}
+		var b = sym.Bounds()
+		drawPolygon(dst, []image.Point{b.Min, {b.Min.X, b.Max.Y - 1}, b.Max.Sub(image.Pt(1, 1)), {b.Max.X - 1, b.Min.Y}}, annotateBounds)
+		drawPolygon(dst, sym.Points, annotateOutline)
+		drawLabel(dst, b, sym.Type.String()+": "+printable(sym.Data))
+	}
+	return dst
+}
+
+/** annotate an image and encode the result as PNG. */
+func WriteAnnotatedPNG(w io.Writer, img image.Image, symbols []Symbol) error {
+	return png.Encode(w, Annotate(img, symbols))
+}
+
+/** draw a closed polygon with 2 pixel wide lines. */
+func drawPolygon(dst *image.RGBA, points []image.Point, c color.RGBA) {
+	for i, p := range points {
+		drawLine(dst, p, points[(i+1)%len(points)], c)
+	}
+}
+
+/** Bresenham's line algorithm, stamping 2x2 pixel dots. */
+func drawLine(dst *image.RGBA, p0, p1 image.Point, c color.RGBA) {
+	var dx, dy = abs(p1.X - p0.X), -abs(p1.Y - p0.Y)
+	var sx, sy = 1, 1
+	if p0.X > p1.X {
+		sx = -1
+	}
+	if p0.Y > p1.Y {
+		sy = -1
+	}
+
+	for err := dx + dy; ; {
+		for _, d := range [4]image.Point{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+			if p := p0.Add(d); p.In(dst.Rect) {
+				dst.SetRGBA(p.X, p.Y, c)
+			}
+		}
+		if p0 == p1 {
+			break
+		}
+		if e2 := 2 * err; e2 >= dy {
+			err += dy
+			p0.X += sx
+		} else {
+			err += dx
+			p0.Y += sy
+		}
+	}
+}
+
+/** draw text on a dark background above box, or inside it when there
+ * is no room above.
+ */
+func drawLabel(dst *image.RGBA, box image.Rectangle, text string) {
+	var metrics = annotateFace.Metrics()
+	var height = (metrics.Ascent + metrics.Descent).Ceil() + 2
+	var width = font.MeasureString(annotateFace, text).Ceil() + 4
+
+	var r = image.Rect(box.Min.X, box.Min.Y-height, box.Min.X+width, box.Min.Y)
+	if r.Min.Y < dst.Rect.Min.Y {
+		r = r.Add(image.Pt(0, height))
+	}
+	if r.Max.X > dst.Rect.Max.X {
+		r = r.Sub(image.Pt(min(r.Max.X-dst.Rect.Max.X, r.Min.X-dst.Rect.Min.X), 0))
+	}
+	draw.Draw(dst, r, image.NewUniform(annotateLabel), image.Point{}, draw.Over)
+
+	var d = font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(annotateText),
+		Face: annotateFace,
+		Dot:  fixed.P(r.Min.X+2, r.Min.Y+1+metrics.Ascent.Ceil()),
+	}
+	d.DrawString(text)
+}
+
+/** label text: control and non-ASCII characters replaced, long data
+ * truncated.
+ */
+func printable(data string) string {
+	var clean = strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return '.'
+		}
+		return r
+	}, data)
+	if len(clean) > annotateMaxText {
+		clean = clean[:annotateMaxText-3] + "..."
+	}
+	return clean
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+/*@}*/
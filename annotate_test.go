@@ -0,0 +1,50 @@
+package zbar
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestAnnotate(t *testing.T) {
+	var img = image.NewGray(image.Rect(0, 0, 200, 100))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	var sym = Symbol{
+		Type:   ZBAR_QRCODE,
+		Data:   "hello\nworld",
+		Points: []image.Point{{50, 40}, {50, 80}, {90, 80}, {90, 40}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteAnnotatedPNG(&buf, img, []Symbol{sym}); err != nil {
+		t.Fatal(err)
+	}
+	out, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var c = color.RGBAModel.Convert(out.At(70, 40)).(color.RGBA)
+	if c.R == 0xff && c.G == 0xff && c.B == 0xff {
+		t.Error("outline not drawn")
+	}
+	// label background above the box
+	if c = color.RGBAModel.Convert(out.At(51, 30)).(color.RGBA); c.R > 0x80 && c.G > 0x80 {
+		t.Errorf("label not drawn: %v", c)
+	}
+	if v := img.Pix[40*img.Stride+70]; v != 0xff {
+		t.Error("source image modified")
+	}
+
+	if s := printable("a\x00bé"); s != "a.b." {
+		t.Errorf("printable = %q", s)
+	}
+	if s := printable(strings.Repeat("x", 100)); len(s) != annotateMaxText {
+		t.Errorf("printable length = %d", len(s))
+	}
+}
@@ -0,0 +1,27 @@
+package zbar
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zooyer/zbar/bcbp"
+)
+
+/*------------------------------------------------------------*/
+/** @name Boarding passes
+ * see package bcbp
+ */
+/*@{*/
+
+/** parse the IATA boarding pass data of a PDF417 or QR code symbol.
+ * flight dates are resolved against ref, usually the scan time.
+ */
+func (sym Symbol) BoardingPass(ref time.Time) (*bcbp.Pass, error) {
+	switch sym.Type & ZBAR_SYMBOL {
+	case ZBAR_PDF417, ZBAR_QRCODE:
+		return bcbp.Parse(sym.Data, ref)
+	}
+	return nil, fmt.Errorf("zbar: %v is not a PDF417 or QR code symbol", sym.Type)
+}
+
+/*@}*/
@@ -0,0 +1,314 @@
+/** Package bcbp parses IATA bar coded boarding passes (Resolution
+ * 792) as printed in PDF417, QR and Aztec symbols.
+ *
+ * a pass has a fixed length mandatory section per flight leg, each
+ * followed by a variable size field holding the conditional items
+ * and airline data.  the conditional section of the first leg starts
+ * with the format version and carries the items unique to the pass;
+ * every leg may carry items repeated per leg.  section sizes are hex
+ * encoded, so fields added by later versions are simply absent from
+ * shorter sections.  Julian dates are resolved against a reference
+ * date, usually the time of the scan.
+ */
+package bcbp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/** invalid boarding pass data. */
+type ParseError struct {
+	Offset int
+	Field  string
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("bcbp: offset %d: %s: %s", e.Offset, e.Field, e.Msg)
+}
+
+/** boarding pass. */
+type Pass struct {
+	PassengerName string // "SURNAME/GIVEN NAMES"
+	ETicket       bool
+	Version       int // 0 without conditional items
+
+	// conditional items unique to the pass
+	PassengerDescription string
+	CheckInSource        string
+	IssuanceSource       string
+	IssueDate            time.Time // zero if absent
+	DocumentType         string    // "B" boarding pass, "I" itinerary receipt
+	Issuer               string    // airline designator of the issuer
+	BagTags              []string  // baggage tag licence plate numbers
+
+	Legs []Leg
+
+	SecurityType string
+	Security     string
+}
+
+/** flight leg. */
+type Leg struct {
+	PNR          string
+	From, To     string // IATA airport codes
+	Carrier      string // operating carrier
+	FlightNumber string
+	Date         time.Time
+	Compartment  string
+	Seat         string
+	Sequence     string // check-in sequence number
+	Status       string // passenger status
+
+	// conditional items repeated per leg
+	AirlineNumeric       string
+	DocumentNumber       string
+	Selectee             string
+	DocumentVerification string
+	MarketingCarrier     string
+	FrequentFlyerAirline string
+	FrequentFlyerNumber  string
+	IDAD                 string
+	BaggageAllowance     string
+	FastTrack            string // version 6+
+
+	AirlineData string // airline individual use
+}
+
+/** surname part of the passenger name. */
+func (p *Pass) Surname() string {
+	var surname, _, _ = strings.Cut(p.PassengerName, "/")
+	return surname
+}
+
+/** given names part of the passenger name. */
+func (p *Pass) GivenName() string {
+	var _, given, _ = strings.Cut(p.PassengerName, "/")
+	return given
+}
+
+/** field reader. */
+type reader struct {
+	data string
+	pos  int
+	end  int
+}
+
+/** next n characters, space trimmed. */
+func (r *reader) take(n int, field string) (string, error) {
+	if r.pos+n > r.end {
+		return "", &ParseError{r.pos, field, fmt.Sprintf("want %d characters, have %d", n, r.end-r.pos)}
+	}
+	r.pos += n
+	return strings.TrimSpace(r.data[r.pos-n : r.pos]), nil
+}
+
+/** fields of a section, left empty past its end. */
+func (r *reader) fields(fields ...field) error {
+	for _, f := range fields {
+		if r.pos >= r.end {
+			return nil
+		}
+		var err error
+		if *f.dst, err = r.take(min(f.n, r.end-r.pos), f.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/** two hex digit section size; returns a reader for the section. */
+func (r *reader) section(field string) (*reader, error) {
+	var start = r.pos
+	s, err := r.take(2, field)
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.ParseUint(s, 16, 8)
+	if err != nil {
+		return nil, &ParseError{start, field, fmt.Sprintf("invalid size %q", s)}
+	}
+	if r.pos+int(n) > r.end {
+		return nil, &ParseError{start, field, fmt.Sprintf("size %d exceeds remaining %d characters", n, r.end-r.pos)}
+	}
+	var sub = &reader{r.data, r.pos, r.pos + int(n)}
+	r.pos += int(n)
+	return sub, nil
+}
+
+type field struct {
+	dst  *string
+	n    int
+	name string
+}
+
+/** parse a boarding pass, resolving dates against ref. */
+func Parse(data string, ref time.Time) (*Pass, error) {
+	var r = &reader{data, 0, len(data)}
+	var p = &Pass{}
+
+	format, err := r.take(1, "format code")
+	if err != nil {
+		return nil, err
+	}
+	if format != "M" {
+		return nil, &ParseError{0, "format code", fmt.Sprintf("unsupported format %q", format)}
+	}
+	legs, err := r.take(1, "number of legs")
+	if err != nil {
+		return nil, err
+	}
+	var n, _ = strconv.Atoi(legs)
+	if n < 1 {
+		return nil, &ParseError{1, "number of legs", fmt.Sprintf("invalid count %q", legs)}
+	}
+	var eticket string
+	if err = r.fields(field{&p.PassengerName, 20, "passenger name"}, field{&eticket, 1, "electronic ticket indicator"}); err != nil {
+		return nil, err
+	}
+	if r.pos < 22 {
+		return nil, &ParseError{r.pos, "electronic ticket indicator", "truncated"}
+	}
+	p.ETicket = eticket == "E"
+
+	for i := 0; i < n; i++ {
+		leg, err := p.leg(r, i, ref)
+		if err != nil {
+			return nil, err
+		}
+		p.Legs = append(p.Legs, *leg)
+	}
+
+	if r.pos < r.end {
+		if r.data[r.pos] != '^' {
+			return nil, &ParseError{r.pos, "security data", "missing '^'"}
+		}
+		r.pos++
+		if p.SecurityType, err = r.take(1, "type of security data"); err != nil {
+			return nil, err
+		}
+		sec, err := r.section("length of security data")
+		if err != nil {
+			return nil, err
+		}
+		p.Security = sec.data[sec.pos:sec.end]
+	}
+	return p, nil
+}
+
+/** mandatory items and variable size field of leg i. */
+func (p *Pass) leg(r *reader, i int, ref time.Time) (*Leg, error) {
+	var leg = &Leg{}
+	var julian string
+	if r.end-r.pos < 35 {
+		return nil, &ParseError{r.pos, fmt.Sprintf("leg %d", i+1), "truncated mandatory items"}
+	}
+	if err := r.fields(
+		field{&leg.PNR, 7, "PNR code"},
+		field{&leg.From, 3, "from city airport code"},
+		field{&leg.To, 3, "to city airport code"},
+		field{&leg.Carrier, 3, "operating carrier designator"},
+		field{&leg.FlightNumber, 5, "flight number"},
+		field{&julian, 3, "date of flight"},
+		field{&leg.Compartment, 1, "compartment code"},
+		field{&leg.Seat, 4, "seat number"},
+		field{&leg.Sequence, 5, "check-in sequence number"},
+		field{&leg.Status, 1, "passenger status"},
+	); err != nil {
+		return nil, err
+	}
+	var day, err = strconv.Atoi(julian)
+	if err != nil || day < 1 || day > 366 {
+		return nil, &ParseError{r.pos - 14, "date of flight", fmt.Sprintf("invalid Julian date %q", julian)}
+	}
+	leg.Date = JulianDate(day, ref)
+
+	v, err := r.section("field size of variable size field")
+	if err != nil {
+		return nil, err
+	}
+	if i == 0 && v.pos < v.end {
+		if err = p.unique(v, ref); err != nil {
+			return nil, err
+		}
+	}
+	if v.pos < v.end {
+		rep, err := v.section("field size of repeated conditional items")
+		if err != nil {
+			return nil, err
+		}
+		var fields = []field{
+			{&leg.AirlineNumeric, 3, "airline numeric code"},
+			{&leg.DocumentNumber, 10, "document form/serial number"},
+			{&leg.Selectee, 1, "selectee indicator"},
+			{&leg.DocumentVerification, 1, "international documentation verification"},
+			{&leg.MarketingCarrier, 3, "marketing carrier designator"},
+			{&leg.FrequentFlyerAirline, 3, "frequent flyer airline designator"},
+			{&leg.FrequentFlyerNumber, 16, "frequent flyer number"},
+			{&leg.IDAD, 1, "ID/AD indicator"},
+			{&leg.BaggageAllowance, 3, "free baggage allowance"},
+		}
+		if p.Version >= 6 {
+			fields = append(fields, field{&leg.FastTrack, 1, "fast track"})
+		}
+		if err = rep.fields(fields...); err != nil {
+			return nil, err
+		}
+	}
+	leg.AirlineData = v.data[v.pos:v.end]
+	return leg, nil
+}
+
+/** version and conditional items unique to the pass. */
+func (p *Pass) unique(v *reader, ref time.Time) error {
+	if v.data[v.pos] != '>' {
+		return &ParseError{v.pos, "beginning of version number", "missing '>'"}
+	}
+	v.pos++
+	version, err := v.take(1, "version number")
+	if err != nil {
+		return err
+	}
+	if p.Version, err = strconv.Atoi(version); err != nil || p.Version == 0 {
+		return &ParseError{v.pos - 1, "version number", fmt.Sprintf("invalid version %q", version)}
+	}
+
+	u, err := v.section("field size of unique conditional items")
+	if err != nil {
+		return err
+	}
+	var issued string
+	var tags [3]string
+	var fields = []field{
+		{&p.PassengerDescription, 1, "passenger description"},
+		{&p.CheckInSource, 1, "source of check-in"},
+		{&p.IssuanceSource, 1, "source of boarding pass issuance"},
+		{&issued, 4, "date of issue of boarding pass"},
+		{&p.DocumentType, 1, "document type"},
+		{&p.Issuer, 3, "airline designator of boarding pass issuer"},
+		{&tags[0], 13, "baggage tag licence plate numbers"},
+		{&tags[1], 13, "first non-consecutive baggage tag licence plate numbers"},
+		{&tags[2], 13, "second non-consecutive baggage tag licence plate numbers"},
+	}
+	var start = u.pos
+	if err = u.fields(fields...); err != nil {
+		return err
+	}
+
+	if issued != "" {
+		var n, err = strconv.Atoi(issued)
+		if err != nil || len(issued) != 4 || n%1000 < 1 || n%1000 > 366 {
+			return &ParseError{start + 3, "date of issue of boarding pass", fmt.Sprintf("invalid Julian date %q", issued)}
+		}
+		p.IssueDate = JulianYearDate(n/1000, n%1000, ref)
+	}
+	for _, tag := range tags {
+		if tag != "" {
+			p.BagTags = append(p.BagTags, tag)
+		}
+	}
+	return nil
+}
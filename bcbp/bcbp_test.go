@@ -0,0 +1,98 @@
+package bcbp
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+/** prefix s with its two hex digit size. */
+func sized(s string) string {
+	return fmt.Sprintf("%02X", len(s)) + s
+}
+
+var ref = time.Date(2025, 11, 20, 9, 30, 0, 0, time.UTC)
+
+func TestParseMandatory(t *testing.T) {
+	p, err := Parse("M1DESMARAIS/LUC       EABC123 YULFRAAC 0834 326J001A0025 100", ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Surname() != "DESMARAIS" || p.GivenName() != "LUC" || !p.ETicket || p.Version != 0 || len(p.Legs) != 1 {
+		t.Fatalf("got %+v", p)
+	}
+	var leg = p.Legs[0]
+	if leg.PNR != "ABC123" || leg.From != "YUL" || leg.To != "FRA" || leg.Carrier != "AC" ||
+		leg.FlightNumber != "0834" || !leg.Date.Equal(time.Date(2025, 11, 22, 0, 0, 0, 0, time.UTC)) ||
+		leg.Compartment != "J" || leg.Seat != "001A" || leg.Sequence != "0025" || leg.Status != "1" {
+		t.Errorf("leg %+v", leg)
+	}
+}
+
+func TestParseMultiLeg(t *testing.T) {
+	var unique = "1WW5320BAC 0014123456003"
+	var repeated1 = "0141234567890" + "10" + "AC AC " + "1234567890123   " + "20PC" + "Y"
+	var repeated2 = "0140987654321" + "10" + "LH LH " + "992000123456    " + "01PC" + "N"
+	var data = "M2DESMARAIS/LUC       E" +
+		"ABC123 YULFRAAC 0834 326J001A0025 1" + sized(">6"+sized(unique)+sized(repeated1)+"LX58Z") +
+		"DEF456 FRAGVALH 3664 327C012C0002 1" + sized(sized(repeated2)) +
+		"^1" + sized("GIWVC5EH7JNT684FVNJ9")
+
+	p, err := Parse(data, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Version != 6 || p.PassengerDescription != "1" || p.CheckInSource != "W" ||
+		!p.IssueDate.Equal(time.Date(2025, 11, 16, 0, 0, 0, 0, time.UTC)) || p.DocumentType != "B" ||
+		p.Issuer != "AC" || len(p.BagTags) != 1 || p.BagTags[0] != "0014123456003" ||
+		p.SecurityType != "1" || p.Security != "GIWVC5EH7JNT684FVNJ9" || len(p.Legs) != 2 {
+		t.Fatalf("got %+v", p)
+	}
+	var l1, l2 = p.Legs[0], p.Legs[1]
+	if l1.AirlineNumeric != "014" || l1.DocumentNumber != "1234567890" || l1.FrequentFlyerNumber != "1234567890123" ||
+		l1.BaggageAllowance != "0PC" || l1.FastTrack != "Y" || l1.AirlineData != "LX58Z" {
+		t.Errorf("leg 1 %+v", l1)
+	}
+	if l2.From != "FRA" || l2.To != "GVA" || l2.Carrier != "LH" || l2.Seat != "012C" ||
+		!l2.Date.Equal(time.Date(2025, 11, 23, 0, 0, 0, 0, time.UTC)) ||
+		l2.MarketingCarrier != "LH" || l2.BaggageAllowance != "1PC" || l2.FastTrack != "N" {
+		t.Errorf("leg 2 %+v", l2)
+	}
+}
+
+func TestParseShortSections(t *testing.T) {
+	// version 2 pass with truncated unique and repeated sections
+	var data = "M1DOE/JANE             " + "XYZ789 JFKLHRBA 0178 001Y023F0100 1" + sized(">2"+sized("1C")+sized("125"))
+	p, err := Parse(data, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.ETicket || p.Version != 2 || p.CheckInSource != "C" || !p.IssueDate.IsZero() ||
+		p.Legs[0].AirlineNumeric != "125" || p.Legs[0].DocumentNumber != "" ||
+		!p.Legs[0].Date.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("got %+v", p)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	const leg = "ABC123 YULFRAAC 0834 326J001A0025 1"
+	const head = "M1DESMARAIS/LUC       E"
+	for data, offset := range map[string]int{
+		"S1DESMARAIS/LUC       E" + leg + "00":         0,
+		"MXDESMARAIS/LUC       E" + leg + "00":         1,
+		"M1DESMARAIS":                                  11,
+		head + "ABC123 YULFRA":                         23,
+		head + "ABC123 YULFRAAC 0834 3X6J001A0025 100": 44,
+		head + leg + "0G":                              58,
+		head + leg + "05>6":                            58,
+		head + leg + "03<60":                           60,
+		head + leg + "03>6X":                           62,
+		head + leg + "00X":                             60,
+		head + leg + sized(">6"+sized("1WW5A20B")):     67,
+	} {
+		_, err := Parse(data, ref)
+		if e, ok := err.(*ParseError); !ok || e.Offset != offset {
+			t.Errorf("%q: %v, want error at %d", data, err, offset)
+		}
+	}
+}
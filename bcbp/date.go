@@ -0,0 +1,52 @@
+package bcbp
+
+import "time"
+
+/** resolve a day of the year (1 to 366) without a year to the date
+ * closest to ref.
+ */
+func JulianDate(day int, ref time.Time) time.Time {
+	var ry, rm, rd = ref.Date()
+	ref = time.Date(ry, rm, rd, 0, 0, 0, 0, time.UTC)
+
+	var best time.Time
+	for year := ry - 1; year <= ry+1; year++ {
+		if day > daysIn(year) {
+			continue
+		}
+		var t = time.Date(year, 1, day, 0, 0, 0, 0, time.UTC)
+		if best.IsZero() || abs(t.Sub(ref)) < abs(best.Sub(ref)) {
+			best = t
+		}
+	}
+	return best
+}
+
+/** resolve a day of the year with the last digit of its year
+ * ("YDDD", eg 6325) to the latest such date not after ref.
+ */
+func JulianYearDate(digit, day int, ref time.Time) time.Time {
+	var ry, rm, rd = ref.Date()
+	ref = time.Date(ry, rm, rd, 0, 0, 0, 0, time.UTC)
+
+	for year := ry - (ry-digit)%10; year > ry-20; year -= 10 {
+		if day > daysIn(year) {
+			continue
+		}
+		if t := time.Date(year, 1, day, 0, 0, 0, 0, time.UTC); !t.After(ref) {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func daysIn(year int) int {
+	return time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+}
+
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
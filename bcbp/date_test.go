@@ -0,0 +1,37 @@
+package bcbp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJulianDate(t *testing.T) {
+	var ref = time.Date(2025, 12, 28, 15, 0, 0, 0, time.UTC)
+	for day, want := range map[int]string{
+		362: "2025-12-28",
+		2:   "2026-01-02",
+		300: "2025-10-27",
+		366: "2024-12-31",
+	} {
+		if got := JulianDate(day, ref).Format("2006-01-02"); got != want {
+			t.Errorf("%d: %s, want %s", day, got, want)
+		}
+	}
+}
+
+func TestJulianYearDate(t *testing.T) {
+	var ref = time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		digit, day int
+		want       string
+	}{
+		{5, 59, "2025-02-28"},
+		{5, 61, "2015-03-02"},
+		{4, 366, "2024-12-31"},
+		{9, 1, "2019-01-01"},
+	} {
+		if got := JulianYearDate(tc.digit, tc.day, ref).Format("2006-01-02"); got != tc.want {
+			t.Errorf("%d%03d: %s, want %s", tc.digit, tc.day, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package zbar
+
+// #include <zbar.h>
+// extern void zbarImageCleanup(zbar_image_t *image);
+// extern void zbarImageData(zbar_image_t *image, void *owner);
+// extern void zbarDecoderData(zbar_decoder_t *decoder);
+import "C"
+import (
+	"sync"
+	"unsafe"
+)
+
+/*------------------------------------------------------------*/
+/** @name Callbacks
+ * the library is handed exported trampolines instead of Go function
+ * values.  Go handlers are kept here, keyed by the C object they were
+ * registered on; data handlers get that object as their C userdata
+ * and the application's userdata is kept alongside the handler
+ */
+/*@{*/
+
+/** Go data handler with the userdata it was registered with. */
+type dataHandler struct {
+	handler  ZBarImageDataHandler
+	userData unsafe.Pointer
+}
+
+var (
+	handlerLock     sync.Mutex
+	cleanupHandlers = map[unsafe.Pointer]ZBarImageCleanupHandler{} // by image
+	dataHandlers    = map[unsafe.Pointer]*dataHandler{}            // by image scanner or processor
+	decoderHandlers = map[unsafe.Pointer]ZBarDecoderHandler{}      // by decoder
+)
+
+//export zbarImageCleanup
+func zbarImageCleanup(image *C.zbar_image_t) {
+	handlerLock.Lock()
+	var handler = cleanupHandlers[unsafe.Pointer(image)]
+	delete(cleanupHandlers, unsafe.Pointer(image))
+	handlerLock.Unlock()
+
+	if handler != nil {
+		handler((*ZBarImage)(unsafe.Pointer(image)))
+	}
+}
+
+//export zbarImageData
+func zbarImageData(image *C.zbar_image_t, owner unsafe.Pointer) {
+	handlerLock.Lock()
+	var h = dataHandlers[owner]
+	handlerLock.Unlock()
+
+	if h != nil {
+		h.handler((*ZBarImage)(unsafe.Pointer(image)), h.userData)
+	}
+}
+
+//export zbarDecoderData
+func zbarDecoderData(decoder *C.zbar_decoder_t) {
+	handlerLock.Lock()
+	var handler = decoderHandlers[unsafe.Pointer(decoder)]
+	handlerLock.Unlock()
+
+	if handler != nil {
+		handler((*ZBarDecoder)(unsafe.Pointer(decoder)))
+	}
+}
+
+/** set the image data, with a Go cleanup handler (or nil). */
+func setImageCleanup(image *ZBarImage, data unsafe.Pointer, length uint64, handler ZBarImageCleanupHandler) {
+	var cleanup *C.zbar_image_cleanup_handler_t
+	if handler != nil {
+		cleanup = (*C.zbar_image_cleanup_handler_t)(C.zbarImageCleanup)
+	}
+	// replacing the data runs the previous handler, so register after
+	C.zbar_image_set_data((*C.zbar_image_t)(unsafe.Pointer(image)), data, C.ulong(length), cleanup)
+	if handler != nil {
+		handlerLock.Lock()
+		cleanupHandlers[unsafe.Pointer(image)] = handler
+		handlerLock.Unlock()
+	}
+}
+
+/** register a Go data handler (or nil) for owner.
+ * set installs the trampoline (or NULL) and owner as C userdata.
+ * @returns the previously registered Go handler
+ */
+func setDataHandler(owner unsafe.Pointer, handler ZBarImageDataHandler, userData unsafe.Pointer, set func(fn *C.zbar_image_data_handler_t, userData unsafe.Pointer)) ZBarImageDataHandler {
+	handlerLock.Lock()
+	var prev ZBarImageDataHandler
+	if h := dataHandlers[owner]; h != nil {
+		prev = h.handler
+	}
+	if handler != nil {
+		dataHandlers[owner] = &dataHandler{handler, userData}
+	} else {
+		delete(dataHandlers, owner)
+	}
+	handlerLock.Unlock()
+
+	if handler != nil {
+		set((*C.zbar_image_data_handler_t)(C.zbarImageData), owner)
+	} else {
+		set(nil, userData)
+	}
+	return prev
+}
+
+/** userdata registered with the Go data handler of owner.
+ * @returns false if owner has no Go data handler
+ */
+func handlerUserData(owner unsafe.Pointer) (unsafe.Pointer, bool) {
+	handlerLock.Lock()
+	defer handlerLock.Unlock()
+	if h := dataHandlers[owner]; h != nil {
+		return h.userData, true
+	}
+	return nil, false
+}
+
+/** replace the userdata of the Go data handler of owner.
+ * @returns false if owner has no Go data handler
+ */
+func setHandlerUserData(owner unsafe.Pointer, userData unsafe.Pointer) bool {
+	handlerLock.Lock()
+	defer handlerLock.Unlock()
+	if h := dataHandlers[owner]; h != nil {
+		h.userData = userData
+		return true
+	}
+	return false
+}
+
+/** register a Go decoder handler (or nil).
+ * @returns the previously registered Go handler
+ */
+func setDecoderHandler(decoder *ZBarDecoder, handler ZBarDecoderHandler) ZBarDecoderHandler {
+	handlerLock.Lock()
+	var prev = decoderHandlers[unsafe.Pointer(decoder)]
+	if handler != nil {
+		decoderHandlers[unsafe.Pointer(decoder)] = handler
+	} else {
+		delete(decoderHandlers, unsafe.Pointer(decoder))
+	}
+	handlerLock.Unlock()
+
+	var fn *C.zbar_decoder_handler_t
+	if handler != nil {
+		fn = (*C.zbar_decoder_handler_t)(C.zbarDecoderData)
+	}
+	C.zbar_decoder_set_handler((*C.zbar_decoder_t)(unsafe.Pointer(decoder)), fn)
+	return prev
+}
+
+/** drop the Go handlers of a destroyed object. */
+func forgetHandlers(owner unsafe.Pointer) {
+	handlerLock.Lock()
+	delete(dataHandlers, owner)
+	delete(decoderHandlers, owner)
+	handlerLock.Unlock()
+}
+
+/*@}*/
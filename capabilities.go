@@ -0,0 +1,197 @@
+package zbar
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+/*------------------------------------------------------------*/
+/** @name Library capabilities
+ * features differ between libzbar releases and builds (eg without
+ * JPEG support), and the library silently ignores configs for
+ * symbologies it does not know.  the capabilities of the loaded
+ * library are probed once so applications can fail at startup
+ * instead
+ */
+/*@{*/
+
+/** features supported by the loaded library. */
+type Capability struct {
+	Major, Minor uint32           // library version, see ZBarVersion()
+	Symbologies  []ZBarSymbolType // symbologies that can be enabled
+	Configs      []ZBarConfig     // configs accepted by the image scanner
+	Formats      []string         // fourccs images can be converted from and to
+}
+
+/** symbologies probed by Capabilities(). */
+var probeSymbologies = []ZBarSymbolType{
+	ZBAR_EAN2, ZBAR_EAN5, ZBAR_EAN8, ZBAR_UPCE, ZBAR_ISBN10, ZBAR_UPCA,
+	ZBAR_EAN13, ZBAR_ISBN13, ZBAR_COMPOSITE, ZBAR_I25, ZBAR_DATABAR,
+	ZBAR_DATABAR_EXP, ZBAR_CODABAR, ZBAR_CODE39, ZBAR_PDF417, ZBAR_QRCODE,
+	ZBAR_SQCODE, ZBAR_CODE93, ZBAR_CODE128,
+}
+
+/** configs probed by Capabilities(). */
+var probeConfigs = []ZBarConfig{
+	ZBAR_CFG_ENABLE, ZBAR_CFG_ADD_CHECK, ZBAR_CFG_EMIT_CHECK,
+	ZBAR_CFG_ASCII, ZBAR_CFG_BINARY, ZBAR_CFG_MIN_LEN, ZBAR_CFG_MAX_LEN,
+	ZBAR_CFG_UNCERTAINTY, ZBAR_CFG_POSITION, ZBAR_CFG_TEST_INVERTED,
+	ZBAR_CFG_X_DENSITY, ZBAR_CFG_Y_DENSITY,
+}
+
+/** image formats probed by Capabilities(). */
+var probeFormats = []string{
+	"Y800", "GREY", "I420", "YV12", "NV12", "NV21", "YUYV", "UYVY",
+	"RGB3", "BGR3", "RGB4", "BGR4", "RGBP", "RGBR",
+}
+
+var (
+	capabilityOnce sync.Once
+	capability     Capability
+
+	versionOnce                sync.Once
+	versionMajor, versionMinor uint32
+)
+
+/** version of the loaded library, see zbar_version(). */
+func libraryVersion() (major, minor uint32) {
+	versionOnce.Do(func() {
+		ZBarVersion(&versionMajor, &versionMinor)
+	})
+	return versionMajor, versionMinor
+}
+
+/** report whether the loaded library is major.minor or later. */
+func libraryAtLeast(major, minor uint32) bool {
+	var ma, mi = libraryVersion()
+	return ma > major || ma == major && mi >= minor
+}
+
+/** report whether config may be passed to the loaded library.
+ * constants whose value meant something else in older releases are
+ * held back.
+ */
+func configSupported(config ZBarConfig) bool {
+	return config != ZBAR_CFG_BINARY || libraryAtLeast(0, 23)
+}
+
+/** report the features of the loaded library.
+ * the library is probed on the first call: a symbology is supported
+ * if it can be enabled, a config if the image scanner accepts it for
+ * some symbology, and an image format if a grayscale image can be
+ * converted to and back from it.
+ */
+func Capabilities() Capability {
+	capabilityOnce.Do(func() {
+		capability = probeCapabilities()
+	})
+	return capability
+}
+
+func probeCapabilities() Capability {
+	var c Capability
+	c.Major, c.Minor = libraryVersion()
+
+	var scanner = ZBarImageScannerCreate()
+	defer ZBarImageScannerDestroy(scanner)
+
+	for _, sym := range probeSymbologies {
+		if ZBarImageScannerSetConfig(scanner, sym, ZBAR_CFG_ENABLE, 1) == 0 {
+			c.Symbologies = append(c.Symbologies, sym)
+		}
+	}
+
+	for _, cfg := range probeConfigs {
+		// decoder configs given for ZBAR_NONE are applied to every
+		// symbology and always succeed, so probe them one by one
+		var targets = c.Symbologies
+		if cfg >= ZBAR_CFG_POSITION {
+			targets = []ZBarSymbolType{ZBAR_NONE}
+		}
+		for _, sym := range targets {
+			if ZBarImageScannerSetConfig(scanner, sym, cfg, 1) == 0 {
+				c.Configs = append(c.Configs, cfg)
+				break
+			}
+		}
+	}
+
+	for _, format := range probeFormats {
+		if probeFormat(format) {
+			c.Formats = append(c.Formats, format)
+		}
+	}
+	return c
+}
+
+/** convert a small grayscale image to format and back. */
+func probeFormat(format string) bool {
+	var img = ZBarImageCreate()
+	defer ZBarImageDestroy(img)
+	ZBarImageSetFormat(img, fourccY800)
+	ZBarImageSetSize(img, 8, 8)
+	setImageData(img, make([]byte, 8*8))
+
+	var fourcc = ZBarFourcc(format[0], format[1], format[2], format[3])
+	var conv = ZBarImageConvert(img, fourcc)
+	if conv == nil {
+		return false
+	}
+	defer ZBarImageDestroy(conv)
+
+	var back = ZBarImageConvert(conv, fourccY800)
+	if back == nil {
+		return false
+	}
+	ZBarImageDestroy(back)
+	return true
+}
+
+/** check whether a symbology is supported. */
+func (c Capability) HasSymbology(sym ZBarSymbolType) bool {
+	for _, s := range c.Symbologies {
+		if s == sym {
+			return true
+		}
+	}
+	return false
+}
+
+/** check whether a config is supported. */
+func (c Capability) HasConfig(cfg ZBarConfig) bool {
+	for _, s := range c.Configs {
+		if s == cfg {
+			return true
+		}
+	}
+	return false
+}
+
+/** check whether an image format (fourcc, eg "YUYV") is supported. */
+func (c Capability) HasFormat(format string) bool {
+	for _, s := range c.Formats {
+		if s == format {
+			return true
+		}
+	}
+	return false
+}
+
+/** check that all symbologies are supported.
+ * @returns an error naming the missing symbologies
+ */
+func (c Capability) Require(symbologies ...ZBarSymbolType) error {
+	var missing []string
+	for _, sym := range symbologies {
+		if !c.HasSymbology(sym) {
+			missing = append(missing, sym.String())
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("zbar: libzbar %d.%d does not support %s", c.Major, c.Minor, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+/*@}*/
@@ -0,0 +1,52 @@
+package zbar
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCapability(t *testing.T) {
+	var c = Capability{
+		Major:       0,
+		Minor:       10,
+		Symbologies: []ZBarSymbolType{ZBAR_EAN13, ZBAR_QRCODE},
+		Configs:     []ZBarConfig{ZBAR_CFG_ENABLE, ZBAR_CFG_X_DENSITY},
+		Formats:     []string{"Y800", "YUYV"},
+	}
+
+	if !c.HasSymbology(ZBAR_QRCODE) || c.HasSymbology(ZBAR_CODE93) {
+		t.Error("HasSymbology")
+	}
+	if !c.HasConfig(ZBAR_CFG_X_DENSITY) || c.HasConfig(ZBAR_CFG_TEST_INVERTED) {
+		t.Error("HasConfig")
+	}
+	if !c.HasFormat("YUYV") || c.HasFormat("JPEG") {
+		t.Error("HasFormat")
+	}
+
+	if err := c.Require(ZBAR_EAN13, ZBAR_QRCODE); err != nil {
+		t.Error(err)
+	}
+	var err = c.Require(ZBAR_QRCODE, ZBAR_SQCODE, ZBAR_CODE93)
+	if err == nil || !strings.Contains(err.Error(), "0.10 does not support SQ-Code, CODE-93") {
+		t.Errorf("Require = %v", err)
+	}
+}
+
+func TestConfigSupported(t *testing.T) {
+	var major, minor = libraryVersion()
+	defer func() { versionMajor, versionMinor = major, minor }()
+
+	versionMajor, versionMinor = 0, 10
+	if configSupported(ZBAR_CFG_BINARY) || !configSupported(ZBAR_CFG_ASCII) {
+		t.Error("0.10")
+	}
+	if ZBarImageScannerSetConfig(nil, ZBAR_NONE, ZBAR_CFG_BINARY, 1) == 0 {
+		t.Error("ZBAR_CFG_BINARY passed to 0.10")
+	}
+
+	versionMajor, versionMinor = 0, 23
+	if !configSupported(ZBAR_CFG_BINARY) {
+		t.Error("0.23")
+	}
+}
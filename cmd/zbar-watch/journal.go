@@ -0,0 +1,152 @@
+//go:build linux
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+/** identity of a processed file.
+ * a file is considered new again if it is replaced with different
+ * content, which is approximated by its size and modification time.
+ */
+type fileKey struct {
+	Size    int64
+	ModTime int64
+}
+
+func keyOf(info os.FileInfo) fileKey {
+	return fileKey{Size: info.Size(), ModTime: info.ModTime().UnixNano()}
+}
+
+/** append-only record of processed files.
+ * each line is "<size>\t<mtime>\t<status>\t<name>".  an entry is only
+ * written (and synced) after the result for a file is on disk, so a
+ * file whose processing was interrupted is picked up again on restart.
+ */
+type journal struct {
+	file    *os.File
+	entries map[string]entry
+}
+
+type entry struct {
+	key    fileKey
+	status string
+}
+
+/** open the journal at path, loading and compacting existing entries.
+ * entries for files that are no longer present in dir are dropped.
+ */
+func openJournal(path, dir string) (*journal, error) {
+	var j = &journal{entries: make(map[string]entry)}
+
+	if err := j.load(path); err != nil {
+		return nil, err
+	}
+
+	for name, e := range j.entries {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil || keyOf(info) != e.key {
+			delete(j.entries, name)
+		}
+	}
+
+	if err := j.rewrite(path); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	j.file = file
+
+	return j, nil
+}
+
+func (j *journal) load(path string) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var sc = bufio.NewScanner(file)
+	for sc.Scan() {
+		var fields = strings.SplitN(sc.Text(), "\t", 4)
+		if len(fields) != 4 {
+			// torn write from a crash, ignore
+			continue
+		}
+		size, err1 := strconv.ParseInt(fields[0], 10, 64)
+		mtime, err2 := strconv.ParseInt(fields[1], 10, 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		j.entries[fields[3]] = entry{key: fileKey{Size: size, ModTime: mtime}, status: fields[2]}
+	}
+
+	return sc.Err()
+}
+
+/** replace the journal file with the current entries. */
+func (j *journal) rewrite(path string) error {
+	var tmp = path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	var w = bufio.NewWriter(file)
+	for name, e := range j.entries {
+		fmt.Fprintf(w, "%d\t%d\t%s\t%s\n", e.key.Size, e.key.ModTime, e.status, name)
+	}
+	if err = w.Flush(); err == nil {
+		err = file.Sync()
+	}
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+/** report whether name with the given identity was already processed. */
+func (j *journal) seen(name string, key fileKey) bool {
+	e, ok := j.entries[name]
+	return ok && e.key == key
+}
+
+/** record name as processed with the given status. */
+func (j *journal) record(name string, key fileKey, status string) error {
+	if strings.ContainsAny(name, "\t\n") {
+		return fmt.Errorf("journal: unsupported file name %q", name)
+	}
+
+	var line = fmt.Sprintf("%d\t%d\t%s\t%s\n", key.Size, key.ModTime, status, name)
+	if _, err := j.file.WriteString(line); err != nil {
+		return err
+	}
+	if err := j.file.Sync(); err != nil {
+		return err
+	}
+
+	j.entries[name] = entry{key: key, status: status}
+	return nil
+}
+
+func (j *journal) Close() error {
+	return j.file.Close()
+}
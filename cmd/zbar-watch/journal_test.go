@@ -0,0 +1,56 @@
+//go:build linux
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJournal(t *testing.T) {
+	var dir = t.TempDir()
+	var path = filepath.Join(dir, ".journal")
+
+	for _, name := range []string{"a.tif", "b.tif"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	infoA, _ := os.Stat(filepath.Join(dir, "a.tif"))
+	infoB, _ := os.Stat(filepath.Join(dir, "b.tif"))
+
+	j, err := openJournal(path, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j.seen("a.tif", keyOf(infoA)) {
+		t.Fatal("a.tif seen in empty journal")
+	}
+	if err = j.record("a.tif", keyOf(infoA), "done"); err != nil {
+		t.Fatal(err)
+	}
+	if err = j.record("b.tif", keyOf(infoB), "failed"); err != nil {
+		t.Fatal(err)
+	}
+	j.Close()
+
+	// b.tif was moved away while the daemon was down
+	os.Remove(filepath.Join(dir, "b.tif"))
+
+	j, err = openJournal(path, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+
+	if !j.seen("a.tif", keyOf(infoA)) {
+		t.Error("a.tif not seen after restart")
+	}
+	if _, ok := j.entries["b.tif"]; ok {
+		t.Error("b.tif not compacted")
+	}
+	if j.seen("a.tif", fileKey{Size: infoA.Size() + 1, ModTime: infoA.ModTime().UnixNano()}) {
+		t.Error("replaced a.tif treated as seen")
+	}
+}
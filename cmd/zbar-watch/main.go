@@ -0,0 +1,318 @@
+//go:build linux
+
+/** zbar-watch scans images as they are dropped into a directory.
+ *
+ * new files are detected with inotify once they are closed after
+ * writing (or moved into the directory).  every page of each file is
+ * decoded, scanned with the configured profile and its result is written to a
+ * sidecar "<file>.json".  when -done/-failed are given the file and
+ * its sidecar are moved there instead of staying in place, by copying
+ * when they are on another file system.  processed files are recorded
+ * in a journal so a restart neither rescans old files nor misses files
+ * that arrived while the daemon was down; files whose result could not
+ * be stored are recorded with status "error" and left in place.
+ *
+ * usage:
+ *   zbar-watch -dir /srv/scans [-profile p.json] [-set qrcode.enable=0]
+ *              [-done /srv/done] [-failed /srv/failed] [-first]
+ */
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/zooyer/zbar"
+)
+
+/** repeatable string flag. */
+type stringList []string
+
+func (l *stringList) String() string     { return strings.Join(*l, ",") }
+func (l *stringList) Set(s string) error { *l = append(*l, s); return nil }
+
+/** sidecar result document. */
+type result struct {
+	File    string         `json:"file"`
+	Scanned string         `json:"scanned"`
+	Profile string         `json:"profile,omitempty"`
+	Symbols []symbolResult `json:"symbols"`
+	Error   string         `json:"error,omitempty"`
+}
+
+type symbolResult struct {
+	Type        string   `json:"type"`
+	Data        string   `json:"data"`
+	Quality     int      `json:"quality"`
+	Page        int      `json:"page"`
+	Points      [][2]int `json:"points"`
+	Orientation string   `json:"orientation"`
+	Inverted    bool     `json:"inverted,omitempty"`
+	ISBN        string   `json:"isbn,omitempty"`
+}
+
+type watcher struct {
+	dir       string
+	doneDir   string
+	failedDir string
+	exts      map[string]bool
+	firstHit  bool
+	scanner   *zbar.Scanner
+	journal   *journal
+}
+
+func main() {
+	var (
+		dir         = flag.String("dir", "", "directory to watch (required)")
+		profilePath = flag.String("profile", "", "JSON scanner profile")
+		doneDir     = flag.String("done", "", "move scanned files with results here")
+		failedDir   = flag.String("failed", "", "move files without results here")
+		journalPath = flag.String("journal", "", "state journal (default <dir>/.zbar-watch.journal)")
+		exts        = flag.String("ext", ".tif,.tiff,.png,.jpg,.jpeg,.gif", "comma separated file extensions to scan")
+		firstHit    = flag.Bool("first", false, "stop scanning a multi-page file at the first page with results")
+		configs     stringList
+	)
+	flag.Var(&configs, "set", "extra zbar config, eg qrcode.enable=1 (repeatable)")
+	flag.Parse()
+
+	if *dir == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *journalPath == "" {
+		*journalPath = filepath.Join(*dir, ".zbar-watch.journal")
+	}
+
+	var profile zbar.Profile
+	if *profilePath != "" {
+		data, err := os.ReadFile(*profilePath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err = json.Unmarshal(data, &profile); err != nil {
+			log.Fatalf("%s: %v", *profilePath, err)
+		}
+	}
+	profile.Configs = append(profile.Configs, configs...)
+
+	scanner, err := zbar.NewScanner(&profile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer scanner.Close()
+
+	j, err := openJournal(*journalPath, *dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer j.Close()
+
+	var w = &watcher{
+		dir:       *dir,
+		doneDir:   *doneDir,
+		failedDir: *failedDir,
+		exts:      make(map[string]bool),
+		firstHit:  *firstHit,
+		scanner:   scanner,
+		journal:   j,
+	}
+	for _, ext := range strings.Split(*exts, ",") {
+		w.exts[strings.ToLower(strings.TrimSpace(ext))] = true
+	}
+
+	if err = w.run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+/** watch the directory forever.
+ * the watch is installed before the initial directory listing so no
+ * file can slip through between the two; duplicates are filtered by
+ * the journal.
+ */
+func (w *watcher) run() error {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC)
+	if err != nil {
+		return fmt.Errorf("inotify_init1: %v", err)
+	}
+	defer syscall.Close(fd)
+
+	if _, err = syscall.InotifyAddWatch(fd, w.dir, syscall.IN_CLOSE_WRITE|syscall.IN_MOVED_TO); err != nil {
+		return fmt.Errorf("inotify_add_watch %s: %v", w.dir, err)
+	}
+
+	if err = w.rescan(); err != nil {
+		return err
+	}
+
+	var buf [64 * (syscall.SizeofInotifyEvent + syscall.NAME_MAX + 1)]byte
+	for {
+		n, err := syscall.Read(fd, buf[:])
+		if err == syscall.EINTR {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("inotify read: %v", err)
+		}
+
+		for off := 0; off+syscall.SizeofInotifyEvent <= n; {
+			var ev = (*syscall.InotifyEvent)(unsafe.Pointer(&buf[off]))
+			var name = buf[off+syscall.SizeofInotifyEvent : off+syscall.SizeofInotifyEvent+int(ev.Len)]
+			off += syscall.SizeofInotifyEvent + int(ev.Len)
+
+			if ev.Mask&syscall.IN_Q_OVERFLOW != 0 {
+				log.Printf("inotify queue overflow, rescanning %s", w.dir)
+				if err = w.rescan(); err != nil {
+					return err
+				}
+				continue
+			}
+			if ev.Mask&syscall.IN_ISDIR == 0 && ev.Len > 0 {
+				w.process(string(bytes.TrimRight(name, "\x00")))
+			}
+		}
+	}
+}
+
+/** process every file currently in the directory. */
+func (w *watcher) rescan() error {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.Type().IsRegular() {
+			w.process(e.Name())
+		}
+	}
+	return nil
+}
+
+/** scan a single file unless it is filtered or already processed. */
+func (w *watcher) process(name string) {
+	if strings.HasPrefix(name, ".") || !w.exts[strings.ToLower(filepath.Ext(name))] {
+		return
+	}
+
+	var path = filepath.Join(w.dir, name)
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return
+	}
+	var key = keyOf(info)
+	if w.journal.seen(name, key) {
+		return
+	}
+
+	var res = w.scan(path)
+	var status = "done"
+	var dest = w.doneDir
+	if res.Error != "" || len(res.Symbols) == 0 {
+		status = "failed"
+		dest = w.failedDir
+	}
+
+	if err = w.store(path, dest, res); err != nil {
+		// recorded all the same: the file is not retried until it
+		// changes, rather than on every rescan
+		log.Printf("%s: %v", name, err)
+		status = "error"
+	}
+	if err = w.journal.record(name, key, status); err != nil {
+		log.Printf("%s: journal: %v", name, err)
+		return
+	}
+
+	log.Printf("%s: %s, %d symbol(s)", name, status, len(res.Symbols))
+}
+
+/** decode and scan one file. */
+func (w *watcher) scan(path string) *result {
+	var res = &result{
+		File:    filepath.Base(path),
+		Scanned: time.Now().UTC().Format(time.RFC3339),
+		Profile: w.scanner.Profile().Name,
+		Symbols: []symbolResult{},
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	defer file.Close()
+
+	symbols, err := w.scanner.ScanPages(file, w.firstHit)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	for _, sym := range symbols {
+		var points = make([][2]int, len(sym.Points))
+		for i, pt := range sym.Points {
+			points[i] = [2]int{pt.X, pt.Y}
+		}
+		var isbn string
+		if sym.ISBN != nil {
+			isbn = sym.ISBN.Hyphenated13()
+		}
+		res.Symbols = append(res.Symbols, symbolResult{
+			Type:        sym.Type.String(),
+			Data:        sym.Data,
+			Quality:     sym.Quality,
+			Page:        sym.Sequence,
+			Points:      points,
+			Orientation: sym.Orientation.String(),
+			Inverted:    sym.Inverted,
+			ISBN:        isbn,
+		})
+	}
+
+	return res
+}
+
+/** write the sidecar and, when dest is set, move the file there.
+ * the sidecar is written to a temporary name and renamed so readers
+ * never see a partial result.
+ */
+func (w *watcher) store(path, dest string, res *result) error {
+	data, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	var dir = filepath.Dir(path)
+	if dest != "" {
+		if err = os.MkdirAll(dest, 0755); err != nil {
+			return err
+		}
+		dir = dest
+	}
+
+	var sidecar = filepath.Join(dir, filepath.Base(path)+".json")
+	var tmp = filepath.Join(dir, "."+filepath.Base(sidecar)+".tmp")
+	if err = os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp, sidecar); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if dest != "" {
+		return moveFile(path, filepath.Join(dest, filepath.Base(path)))
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+//go:build linux
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMoveFile(t *testing.T) {
+	var dir = t.TempDir()
+	var src = filepath.Join(dir, "a.tif")
+	var dest = filepath.Join(dir, "done")
+	os.Mkdir(dest, 0755)
+
+	for _, move := range []func(src, dst string) error{moveFile, copyMove} {
+		if err := os.WriteFile(src, []byte("scan"), 0640); err != nil {
+			t.Fatal(err)
+		}
+		var dst = filepath.Join(dest, "a.tif")
+		if err := move(src, dst); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(src); !os.IsNotExist(err) {
+			t.Errorf("source left behind: %v", err)
+		}
+		data, err := os.ReadFile(dst)
+		if err != nil || string(data) != "scan" {
+			t.Errorf("destination = %q, %v", data, err)
+		}
+		if info, _ := os.Stat(dst); info.Mode().Perm() != 0640 {
+			t.Errorf("mode = %v", info.Mode())
+		}
+		os.Remove(dst)
+	}
+
+	// a failed copy keeps the source and leaves no temporary file
+	os.WriteFile(src, []byte("scan"), 0644)
+	if err := copyMove(src, filepath.Join(dir, "missing", "a.tif")); err == nil {
+		t.Error("copy into missing directory succeeded")
+	}
+	if _, err := os.Stat(src); err != nil {
+		t.Errorf("source lost: %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+package zbar
+
+import (
+	"fmt"
+
+	"github.com/zooyer/zbar/code39"
+)
+
+/*------------------------------------------------------------*/
+/** @name Code 39 post-processing
+ * see package code39
+ */
+/*@{*/
+
+/** post-process the data of a Code 39 symbol.
+ * opts should match the scanner configuration: FullASCII only if
+ * ZBAR_CFG_ASCII is disabled, Check only if the check character is
+ * not already verified and stripped (ZBAR_CFG_ADD_CHECK without
+ * ZBAR_CFG_EMIT_CHECK).
+ */
+func (sym Symbol) Code39(opts code39.Options) (*code39.Result, error) {
+	if sym.Type&ZBAR_SYMBOL != ZBAR_CODE39 {
+		return nil, fmt.Errorf("zbar: %v is not a Code 39 symbol", sym.Type)
+	}
+	return code39.Parse(sym.Data, opts)
+}
+
+/*@}*/
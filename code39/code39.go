@@ -0,0 +1,181 @@
+/** Package code39 post-processes Code 39 data: full ASCII shift
+ * pairs, the optional mod 43 check character and the HIBC (Health
+ * Industry Bar Code) LIC and PAS formats.
+ */
+package code39
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/** Code 39 character set, in the order of the mod 43 values. */
+const charset = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ-. $/+%"
+
+var (
+	/** data contains a character outside the Code 39 set. */
+	ErrCharset = errors.New("code39: invalid character")
+	/** the check character does not match. */
+	ErrCheck = errors.New("code39: check character mismatch")
+)
+
+/** invalid full ASCII shift pair. */
+type ShiftError struct {
+	Offset int
+	Pair   string
+}
+
+func (e *ShiftError) Error() string {
+	return fmt.Sprintf("code39: invalid full ASCII pair %q at offset %d", e.Pair, e.Offset)
+}
+
+/** check character handling. */
+type CheckMode int
+
+const (
+	CheckNone     CheckMode = iota /**< data has no check character */
+	CheckRequired                  /**< last character is a check character: verify and strip */
+	CheckOptional                  /**< strip the last character if it is a valid check character */
+)
+
+/** post-processing options. */
+type Options struct {
+	Check     CheckMode
+	FullASCII bool // decode shift pairs (unless the library did, see ZBAR_CFG_ASCII)
+}
+
+/** post-processed symbol data. */
+type Result struct {
+	Data    string // data without check character, full ASCII decoded if requested
+	Raw     string // data as read
+	Check   byte   // stripped check character, 0 if none
+	HIBC    *HIBC  // HIBC data, nil if not an HIBC symbol
+	HIBCErr error  // why data starting with '+' was not parsed as HIBC
+}
+
+/** post-process Code 39 data.
+ * HIBC symbols (starting with '+') are recognized first; they carry
+ * their own mod 43 check character, which is verified and stripped
+ * regardless of opts, and never use full ASCII.
+ */
+func Parse(data string, opts Options) (*Result, error) {
+	var r = &Result{Data: data, Raw: data}
+
+	if strings.HasPrefix(data, "+") {
+		if r.HIBC, r.HIBCErr = ParseHIBC(data); r.HIBCErr == nil {
+			r.Data, r.Check = data[:len(data)-1], r.HIBC.Check
+			return r, nil
+		}
+	}
+
+	switch opts.Check {
+	case CheckRequired, CheckOptional:
+		var valid = len(data) >= 2 && validChars(data)
+		if valid {
+			c, _ := Mod43(data[:len(data)-1])
+			valid = c == data[len(data)-1]
+		}
+		if valid {
+			r.Data, r.Check = data[:len(data)-1], data[len(data)-1]
+		} else if opts.Check == CheckRequired {
+			return nil, ErrCheck
+		}
+	}
+
+	if opts.FullASCII {
+		decoded, err := DecodeFullASCII(r.Data)
+		if err != nil {
+			return nil, err
+		}
+		r.Data = decoded
+	}
+	return r, nil
+}
+
+/** compute the mod 43 check character of data. */
+func Mod43(data string) (byte, error) {
+	var sum int
+	for i := 0; i < len(data); i++ {
+		var v = strings.IndexByte(charset, data[i])
+		if v < 0 {
+			return 0, ErrCharset
+		}
+		sum += v
+	}
+	return charset[sum%43], nil
+}
+
+func validChars(data string) bool {
+	for i := 0; i < len(data); i++ {
+		if strings.IndexByte(charset, data[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+/** decode full ASCII shift pairs: $A-$Z control characters, %A-%Z
+ * and /A-/Z punctuation, +A-+Z lower case letters.
+ */
+func DecodeFullASCII(data string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(data); i++ {
+		var c = data[i]
+		if strings.IndexByte("$%/+", c) < 0 {
+			b.WriteByte(c)
+			continue
+		}
+		if i+1 == len(data) {
+			return "", &ShiftError{i, data[i:]}
+		}
+		d, ok := shifted(c, data[i+1])
+		if !ok {
+			return "", &ShiftError{i, data[i : i+2]}
+		}
+		b.WriteByte(d)
+		i++
+	}
+	return b.String(), nil
+}
+
+/** ASCII character of a shift pair. */
+func shifted(shift, c byte) (byte, bool) {
+	switch shift {
+	case '$':
+		if c >= 'A' && c <= 'Z' {
+			return c - 'A' + 0x01, true
+		}
+	case '+':
+		if c >= 'A' && c <= 'Z' {
+			return c - 'A' + 'a', true
+		}
+	case '/':
+		switch {
+		case c >= 'A' && c <= 'O':
+			return c - 'A' + '!', true
+		case c == 'Z':
+			return ':', true
+		}
+	case '%':
+		switch {
+		case c >= 'A' && c <= 'E':
+			return c - 'A' + 0x1b, true
+		case c >= 'F' && c <= 'J':
+			return c - 'F' + ';', true
+		case c >= 'K' && c <= 'O':
+			return c - 'K' + '[', true
+		case c >= 'P' && c <= 'T':
+			return c - 'P' + '{', true
+		case c == 'U':
+			return 0, true
+		case c == 'V':
+			return '@', true
+		case c == 'W':
+			return '`', true
+		case c >= 'X' && c <= 'Z':
+			return 0x7f, true
+		}
+	}
+	return 0, false
+}
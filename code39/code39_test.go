@@ -0,0 +1,84 @@
+package code39
+
+import (
+	"testing"
+	"time"
+)
+
+/** append the mod 43 check character. */
+func withCheck(t *testing.T, data string) string {
+	c, err := Mod43(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data + string(c)
+}
+
+func TestFullASCII(t *testing.T) {
+	s, err := DecodeFullASCII("+H+E+L+L+O/A%U$M%V")
+	if err != nil || s != "hello!\x00\r@" {
+		t.Errorf("decoded %q %v", s, err)
+	}
+	for _, bad := range []string{"AB+", "$1", "/P"} {
+		if _, err := DecodeFullASCII(bad); err == nil {
+			t.Errorf("%q accepted", bad)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if c, _ := Mod43("CODE 39"); c != 'R' {
+		t.Errorf("mod 43 = %c", c)
+	}
+
+	r, err := Parse("CODE 39R", Options{Check: CheckRequired})
+	if err != nil || r.Data != "CODE 39" || r.Check != 'R' {
+		t.Errorf("required: %+v %v", r, err)
+	}
+	if _, err = Parse("CODE 39X", Options{Check: CheckRequired}); err != ErrCheck {
+		t.Errorf("bad check: %v", err)
+	}
+	if r, _ = Parse("CODE 39X", Options{Check: CheckOptional}); r.Data != "CODE 39X" || r.Check != 0 {
+		t.Errorf("optional: %+v", r)
+	}
+	if r, _ = Parse(withCheck(t, "+A+B"), Options{Check: CheckOptional, FullASCII: true}); r.Data != "ab" {
+		t.Errorf("check and full ASCII: %+v", r)
+	}
+}
+
+func TestHIBC(t *testing.T) {
+	r, err := Parse(withCheck(t, "+A123BJC5D6E71"), Options{FullASCII: true})
+	if err != nil || r.HIBC == nil {
+		t.Fatalf("primary: %+v %v", r, err)
+	}
+	if h := r.HIBC; h.Kind != LICPrimary || h.Labeler != "A123" || h.Product != "BJC5D6E7" || h.UnitOfMeasure != 1 {
+		t.Errorf("primary: %+v", h)
+	}
+
+	r, err = Parse(withCheck(t, "+A123BJC5D6E71/$$3250630LOT7"), Options{})
+	if err != nil || r.HIBC == nil {
+		t.Fatalf("combined: %+v %v", r, err)
+	}
+	if h := r.HIBC; h.Kind != LICCombined || h.Product != "BJC5D6E7" || h.Lot != "LOT7" || !h.Expiry.Equal(time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("combined: %+v", r.HIBC)
+	}
+
+	r, err = Parse(withCheck(t, "+$+8120526SN42L"), Options{})
+	if err != nil || r.HIBC == nil {
+		t.Fatalf("secondary: %+v %v", r, err)
+	}
+	if h := r.HIBC; h.Kind != LICSecondary || h.Quantity != 12 || h.Serial != "SN42" || h.Link != 'L' || !h.Expiry.Equal(time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("secondary: %+v", r.HIBC)
+	}
+
+	r, err = Parse(withCheck(t, "+/ECHB12/KBAU99"), Options{})
+	if err != nil || r.HIBC == nil || r.HIBC.Kind != PAS || len(r.HIBC.Fields) != 2 || r.HIBC.Fields[1] != "KBAU99" {
+		t.Errorf("PAS: %+v %v", r, err)
+	}
+
+	// bad check character: not HIBC, kept as plain data
+	r, err = Parse("+A123BJC5D6E71X", Options{})
+	if err != nil || r.HIBC != nil || r.HIBCErr != ErrCheck {
+		t.Errorf("bad HIBC: %+v %v", r, err)
+	}
+}
@@ -0,0 +1,194 @@
+package code39
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*------------------------------------------------------------*/
+/** @name HIBC
+ * Health Industry Bar Codes: the Labeler Identification Code (LIC)
+ * format for products, with primary (labeler, product, unit of
+ * measure) and secondary (quantity, expiry, lot or serial) data in
+ * one symbol or two linked ones, and the Patient/Provider Application
+ * Standard (PAS) format
+ */
+/*@{*/
+
+/** data is not valid HIBC. */
+var ErrHIBC = errors.New("code39: invalid HIBC data")
+
+/** HIBC format. */
+type HIBCKind int
+
+const (
+	LICPrimary   HIBCKind = iota /**< primary data only */
+	LICSecondary                 /**< secondary data only, linked to a primary symbol */
+	LICCombined                  /**< primary and secondary data */
+	PAS                          /**< patient/provider data */
+)
+
+/** parsed HIBC data. */
+type HIBC struct {
+	Kind HIBCKind
+
+	// primary data
+	Labeler       string // LIC, 4 characters starting with a letter
+	Product       string // product or catalog number
+	UnitOfMeasure int    // packaging level, 0 for the unit of use
+
+	// secondary data
+	Quantity int       // 0 if absent
+	Expiry   time.Time // zero if absent; MMYY dates give the first of the month
+	Lot      string
+	Serial   string
+	Link     byte // check character of the linked primary symbol
+
+	Fields []string // PAS data fields
+	Check  byte     // mod 43 check character
+}
+
+/** parse HIBC data, including its check character. */
+func ParseHIBC(data string) (*HIBC, error) {
+	if len(data) < 3 || data[0] != '+' || !validChars(data) {
+		return nil, ErrHIBC
+	}
+	if c, _ := Mod43(data[:len(data)-1]); c != data[len(data)-1] {
+		return nil, ErrCheck
+	}
+
+	var h = &HIBC{Check: data[len(data)-1]}
+	var body = data[1 : len(data)-1]
+
+	switch {
+	case body[0] == '/':
+		h.Kind = PAS
+		h.Fields = strings.Split(body[1:], "/")
+		return h, nil
+
+	case body[0] == '$' || isDigits(body[:min(5, len(body))]) && len(body) > 5:
+		// secondary symbol: data and link character
+		h.Kind = LICSecondary
+		h.Link = body[len(body)-1]
+		return h, h.secondary(body[:len(body)-1])
+	}
+
+	var primary, secondary, combined = strings.Cut(body, "/")
+	if len(primary) < 6 || len(primary) > 23 || !isAlpha(primary[0]) || !isDigits(primary[len(primary)-1:]) {
+		return nil, ErrHIBC
+	}
+	h.Labeler = primary[:4]
+	h.Product = primary[4 : len(primary)-1]
+	h.UnitOfMeasure = int(primary[len(primary)-1] - '0')
+	if !combined {
+		return h, nil
+	}
+	h.Kind = LICCombined
+	return h, h.secondary(secondary)
+}
+
+/** parse secondary data:
+ *   $lot
+ *   $$[8QQ|9QQQQQ][date]lot   (dates: MMYY or 2-6 prefixed formats)
+ *   $+[8QQ|9QQQQQ][date]serial
+ *   YYJJJlot                  (former format)
+ */
+func (h *HIBC) secondary(s string) error {
+	var serial bool
+	switch {
+	case strings.HasPrefix(s, "$$"):
+		s = s[2:]
+	case strings.HasPrefix(s, "$+"):
+		s, serial = s[2:], true
+	case strings.HasPrefix(s, "$"):
+		h.Lot = s[1:]
+		return nil
+	default:
+		expiry, err := hibcDate(s, "06002", 5)
+		if err != nil {
+			return err
+		}
+		h.Expiry, h.Lot = expiry, s[5:]
+		return nil
+	}
+
+	// quantity
+	for _, q := range []struct {
+		flag byte
+		n    int
+	}{{'8', 2}, {'9', 5}} {
+		if len(s) > q.n && s[0] == q.flag {
+			n, err := strconv.Atoi(s[1 : 1+q.n])
+			if err != nil {
+				return ErrHIBC
+			}
+			h.Quantity, s = n, s[1+q.n:]
+		}
+	}
+
+	// expiry date
+	var layouts = map[byte]struct {
+		layout string
+		n      int
+	}{
+		'2': {"010206", 6},   // MMDDYY
+		'3': {"060102", 6},   // YYMMDD
+		'4': {"06010215", 8}, // YYMMDDHH
+		'5': {"06002", 5},    // YYJJJ
+		'6': {"0600215", 7},  // YYJJJHH
+		'7': {"", 0},         // no date
+	}
+	if len(s) > 0 && s[0] >= '2' && s[0] <= '7' {
+		var l = layouts[s[0]]
+		if l.n > 0 {
+			expiry, err := hibcDate(s[1:], l.layout, l.n)
+			if err != nil {
+				return err
+			}
+			h.Expiry = expiry
+		}
+		s = s[1+l.n:]
+	} else if len(s) >= 4 && (s[0] == '0' || s[0] == '1') {
+		expiry, err := hibcDate(s, "0106", 4) // MMYY
+		if err != nil {
+			return err
+		}
+		h.Expiry, s = expiry, s[4:]
+	}
+
+	if serial {
+		h.Serial = s
+	} else {
+		h.Lot = s
+	}
+	return nil
+}
+
+/** parse the n character date at the start of s. */
+func hibcDate(s, layout string, n int) (time.Time, error) {
+	if len(s) < n || !isDigits(s[:n]) {
+		return time.Time{}, ErrHIBC
+	}
+	t, err := time.Parse(layout, s[:n])
+	if err != nil {
+		return time.Time{}, ErrHIBC
+	}
+	return t, nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return len(s) > 0
+}
+
+func isAlpha(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
+
+/*@}*/
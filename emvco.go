@@ -0,0 +1,25 @@
+package zbar
+
+import (
+	"fmt"
+
+	"github.com/zooyer/zbar/emvco"
+)
+
+/*------------------------------------------------------------*/
+/** @name EMVCo payment QR codes
+ * see package emvco
+ */
+/*@{*/
+
+/** parse the merchant-presented mode payment payload of a QR code
+ * symbol.
+ */
+func (sym Symbol) EMVCo() (*emvco.Payload, error) {
+	if sym.Type&ZBAR_SYMBOL != ZBAR_QRCODE {
+		return nil, fmt.Errorf("zbar: %v is not a QR code symbol", sym.Type)
+	}
+	return emvco.Parse(sym.Data)
+}
+
+/*@}*/
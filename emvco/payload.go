@@ -0,0 +1,408 @@
+package emvco
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/** tip or convenience fee indicator (ID 55). */
+type TipIndicator string
+
+const (
+	TipNone    TipIndicator = ""   /**< not present */
+	TipPrompt  TipIndicator = "01" /**< prompt the consumer for a tip */
+	FeeFixed   TipIndicator = "02" /**< fixed convenience fee (ID 56) */
+	FeePercent TipIndicator = "03" /**< percentage convenience fee (ID 57) */
+)
+
+/** template of nested data objects identified by a globally unique
+ * identifier (sub-ID "00"), eg a merchant account or a payment system
+ * specific template.
+ */
+type Template struct {
+	ID     string
+	GUID   string
+	Fields []DataObject // all nested objects, including the GUID
+}
+
+/** value of the nested object id, "" if absent. */
+func (t *Template) Get(id string) string {
+	for _, f := range t.Fields {
+		if f.ID == id {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+/** merchant account information (IDs 02 to 51).
+ * IDs 02 to 25 are primitive values reserved for card networks, IDs
+ * 26 to 51 are templates.
+ */
+type MerchantAccount struct {
+	ID        string
+	Value     string // primitive value, "" for templates
+	*Template        // nil for primitive values
+}
+
+/** card network or payment system owning the ID. */
+func (m MerchantAccount) Network() string {
+	var id, _ = strconv.Atoi(m.ID)
+	switch {
+	case id >= 2 && id <= 3:
+		return "Visa"
+	case id >= 4 && id <= 5:
+		return "Mastercard"
+	case id >= 9 && id <= 10:
+		return "Discover"
+	case id >= 11 && id <= 12:
+		return "Amex"
+	case id >= 13 && id <= 14:
+		return "JCB"
+	case id >= 15 && id <= 16:
+		return "UnionPay"
+	case id >= 2 && id <= 25:
+		return "EMVCo"
+	case m.Template != nil:
+		return m.GUID
+	}
+	return ""
+}
+
+/** additional data field template (ID 62). */
+type AdditionalData struct {
+	BillNumber          string     // 01
+	MobileNumber        string     // 02
+	StoreLabel          string     // 03
+	LoyaltyNumber       string     // 04
+	ReferenceLabel      string     // 05
+	CustomerLabel       string     // 06
+	TerminalLabel       string     // 07
+	Purpose             string     // 08
+	ConsumerDataRequest string     // 09, any of 'A' (address), 'M' (mobile), 'E' (e-mail)
+	MerchantTaxID       string     // 10
+	MerchantChannel     string     // 11, media, location and presence digits
+	PaymentSystem       []Template // 50 to 99
+}
+
+/** merchant information in an alternate language (ID 64). */
+type LanguageTemplate struct {
+	Language string // ISO 639-1, 00
+	Name     string // 01
+	City     string // 02
+}
+
+/** merchant-presented mode payload. */
+type Payload struct {
+	FormatIndicator  string // 00, always "01"
+	Dynamic          bool   // 01: "12" for a single transaction, "11" (or absent) for reuse
+	MerchantAccounts []MerchantAccount
+	CategoryCode     string // 52, ISO 18245 merchant category code
+	Currency         string // 53, ISO 4217 numeric code
+	Amount           string // 54, "" to let the consumer enter it
+	Tip              TipIndicator
+	FeeFixed         string // 56
+	FeePercent       string // 57
+	Country          string // 58, ISO 3166-1 alpha-2
+	MerchantName     string // 59
+	MerchantCity     string // 60
+	PostalCode       string // 61
+	Additional       *AdditionalData
+	Language         *LanguageTemplate
+	Unreserved       []Template // 80 to 99
+	CRC              uint16
+
+	Objects []DataObject // all top level data objects in order
+}
+
+/** maximum value lengths of the fixed data objects. */
+var maxLengths = map[string]int{
+	"52": 4, "53": 3, "54": 13, "55": 2, "56": 13, "57": 5,
+	"58": 2, "59": 25, "60": 15, "61": 10,
+}
+
+/** maximum value lengths in the additional data field template. */
+var additionalLengths = map[string]int{
+	"01": 25, "02": 25, "03": 25, "04": 25, "05": 25, "06": 25,
+	"07": 25, "08": 25, "09": 3, "10": 20, "11": 3,
+}
+
+/** parse and validate a merchant-presented mode payload. */
+func Parse(data string) (*Payload, error) {
+	var runes = []rune(data)
+	objects, err := Split(runes, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(objects) == 0 {
+		return nil, &ParseError{0, "", "empty payload"}
+	}
+
+	var first, last = objects[0], objects[len(objects)-1]
+	if first.ID != "00" || first.Value != "01" {
+		return nil, &ParseError{first.Offset, first.ID, "payload must start with format indicator 01"}
+	}
+	if last.ID != "63" {
+		return nil, &ParseError{last.Offset, last.ID, "payload must end with CRC (ID 63)"}
+	}
+	var p = &Payload{FormatIndicator: first.Value, Objects: objects}
+	if err = p.checkCRC(runes, last); err != nil {
+		return nil, err
+	}
+
+	var seen = make(map[string]bool)
+	for _, o := range objects[1 : len(objects)-1] {
+		if seen[o.ID] {
+			return nil, &ParseError{o.Offset, o.ID, "duplicate data object"}
+		}
+		seen[o.ID] = true
+		if max, ok := maxLengths[o.ID]; ok && len([]rune(o.Value)) > max {
+			return nil, &ParseError{o.Offset, o.ID, fmt.Sprintf("longer than %d characters", max)}
+		}
+		if err = p.set(o); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, p.validate(objects)
+}
+
+/** verify the CRC over everything up to and including "6304". */
+func (p *Payload) checkCRC(runes []rune, crc DataObject) error {
+	if len(crc.Value) != 4 {
+		return &ParseError{crc.Offset + 2, crc.ID, "CRC must be 4 hex digits"}
+	}
+	value, err := strconv.ParseUint(crc.Value, 16, 16)
+	if err != nil {
+		return &ParseError{crc.Offset + 4, crc.ID, fmt.Sprintf("invalid CRC %q", crc.Value)}
+	}
+	p.CRC = uint16(value)
+	if want := CRC16([]byte(string(runes[:crc.Offset+4]))); p.CRC != want {
+		return &ParseError{crc.Offset + 4, crc.ID, fmt.Sprintf("CRC mismatch: got %04X, want %04X", p.CRC, want)}
+	}
+	return nil
+}
+
+/** store a top level data object. */
+func (p *Payload) set(o DataObject) error {
+	var err error
+	switch n := o.Num(); {
+	case o.ID == "00" || o.ID == "63":
+		return &ParseError{o.Offset, o.ID, "data object out of place"}
+	case o.ID == "01":
+		if o.Value != "11" && o.Value != "12" {
+			return &ParseError{o.Offset + 4, o.ID, fmt.Sprintf("invalid point of initiation %q", o.Value)}
+		}
+		p.Dynamic = o.Value == "12"
+	case n >= 2 && n <= 25:
+		p.MerchantAccounts = append(p.MerchantAccounts, MerchantAccount{ID: o.ID, Value: o.Value})
+	case n >= 26 && n <= 51:
+		t, err := template(o)
+		if err != nil {
+			return err
+		}
+		p.MerchantAccounts = append(p.MerchantAccounts, MerchantAccount{ID: o.ID, Template: t})
+	case o.ID == "52":
+		p.CategoryCode, err = digits(o, 4)
+	case o.ID == "53":
+		p.Currency, err = digits(o, 3)
+	case o.ID == "54":
+		p.Amount, err = amount(o)
+	case o.ID == "55":
+		switch p.Tip = TipIndicator(o.Value); p.Tip {
+		case TipPrompt, FeeFixed, FeePercent:
+		default:
+			return &ParseError{o.Offset + 4, o.ID, fmt.Sprintf("invalid tip indicator %q", o.Value)}
+		}
+	case o.ID == "56":
+		p.FeeFixed, err = amount(o)
+	case o.ID == "57":
+		p.FeePercent, err = amount(o)
+	case o.ID == "58":
+		if len(o.Value) != 2 || strings.ToUpper(o.Value) != o.Value || !isAlpha(o.Value) {
+			return &ParseError{o.Offset + 4, o.ID, fmt.Sprintf("invalid country code %q", o.Value)}
+		}
+		p.Country = o.Value
+	case o.ID == "59":
+		p.MerchantName = o.Value
+	case o.ID == "60":
+		p.MerchantCity = o.Value
+	case o.ID == "61":
+		p.PostalCode = o.Value
+	case o.ID == "62":
+		p.Additional, err = additional(o)
+	case o.ID == "64":
+		p.Language, err = language(o)
+	case n >= 80:
+		t, err := template(o)
+		if err != nil {
+			return err
+		}
+		p.Unreserved = append(p.Unreserved, *t)
+	}
+	// IDs 65 to 79 are reserved for future use and kept in Objects only
+	return err
+}
+
+/** cross-field checks. */
+func (p *Payload) validate(objects []DataObject) error {
+	var end = objects[len(objects)-1].Offset
+	var missing = func(id, what string) error {
+		return &ParseError{end, id, "missing " + what}
+	}
+	switch {
+	case len(p.MerchantAccounts) == 0:
+		return missing("", "merchant account information (IDs 02 to 51)")
+	case p.CategoryCode == "":
+		return missing("52", "merchant category code")
+	case p.Currency == "":
+		return missing("53", "transaction currency")
+	case p.Country == "":
+		return missing("58", "country code")
+	case p.MerchantName == "":
+		return missing("59", "merchant name")
+	case p.MerchantCity == "":
+		return missing("60", "merchant city")
+	case p.Tip == FeeFixed && p.FeeFixed == "":
+		return missing("56", "fixed convenience fee")
+	case p.Tip == FeePercent && p.FeePercent == "":
+		return missing("57", "convenience fee percentage")
+	}
+	for _, o := range objects {
+		if o.ID == "56" && p.Tip != FeeFixed || o.ID == "57" && p.Tip != FeePercent {
+			return &ParseError{o.Offset, o.ID, "convenience fee without matching tip indicator"}
+		}
+	}
+	if p.FeePercent != "" {
+		if v, _ := strconv.ParseFloat(p.FeePercent, 64); v <= 0 || v >= 100 {
+			return &ParseError{p.object("57").Offset + 4, "57", fmt.Sprintf("percentage %s out of range", p.FeePercent)}
+		}
+	}
+	return nil
+}
+
+/** top level data object with the given ID. */
+func (p *Payload) object(id string) DataObject {
+	for _, o := range p.Objects {
+		if o.ID == id {
+			return o
+		}
+	}
+	return DataObject{}
+}
+
+/** split a template value; its GUID (sub-ID 00) is required. */
+func template(o DataObject) (*Template, error) {
+	fields, err := Split([]rune(o.Value), o.Offset+4)
+	if err != nil {
+		return nil, nest(err, o.ID)
+	}
+	var t = &Template{ID: o.ID, Fields: fields}
+	if t.GUID = t.Get("00"); t.GUID == "" {
+		return nil, &ParseError{o.Offset + 4, o.ID + ".00", "missing globally unique identifier"}
+	}
+	if len([]rune(t.GUID)) > 32 {
+		return nil, &ParseError{fields[0].Offset, o.ID + ".00", "globally unique identifier longer than 32 characters"}
+	}
+	return t, nil
+}
+
+/** additional data field template. */
+func additional(o DataObject) (*AdditionalData, error) {
+	fields, err := Split([]rune(o.Value), o.Offset+4)
+	if err != nil {
+		return nil, nest(err, o.ID)
+	}
+	var a = &AdditionalData{}
+	var targets = map[string]*string{
+		"01": &a.BillNumber, "02": &a.MobileNumber, "03": &a.StoreLabel,
+		"04": &a.LoyaltyNumber, "05": &a.ReferenceLabel, "06": &a.CustomerLabel,
+		"07": &a.TerminalLabel, "08": &a.Purpose, "09": &a.ConsumerDataRequest,
+		"10": &a.MerchantTaxID, "11": &a.MerchantChannel,
+	}
+	for _, f := range fields {
+		var id = o.ID + "." + f.ID
+		if max, ok := additionalLengths[f.ID]; ok && len([]rune(f.Value)) > max {
+			return nil, &ParseError{f.Offset, id, fmt.Sprintf("longer than %d characters", max)}
+		}
+		if target, ok := targets[f.ID]; ok {
+			*target = f.Value
+			continue
+		}
+		if f.Num() >= 50 {
+			t, err := template(f)
+			if err != nil {
+				return nil, nest(err, o.ID)
+			}
+			a.PaymentSystem = append(a.PaymentSystem, *t)
+		}
+	}
+	if strings.Trim(a.ConsumerDataRequest, "AME") != "" {
+		return nil, &ParseError{o.Offset, o.ID + ".09", fmt.Sprintf("invalid consumer data request %q", a.ConsumerDataRequest)}
+	}
+	return a, nil
+}
+
+/** merchant information language template. */
+func language(o DataObject) (*LanguageTemplate, error) {
+	fields, err := Split([]rune(o.Value), o.Offset+4)
+	if err != nil {
+		return nil, nest(err, o.ID)
+	}
+	var l = &LanguageTemplate{}
+	for _, f := range fields {
+		switch f.ID {
+		case "00":
+			l.Language = f.Value
+		case "01":
+			l.Name = f.Value
+		case "02":
+			l.City = f.Value
+		}
+	}
+	switch {
+	case len(l.Language) != 2:
+		return nil, &ParseError{o.Offset + 4, o.ID + ".00", "missing or invalid language preference"}
+	case l.Name == "":
+		return nil, &ParseError{o.Offset + 4, o.ID + ".01", "missing alternate merchant name"}
+	}
+	return l, nil
+}
+
+/** qualify the ID of an error in a nested data object. */
+func nest(err error, parent string) error {
+	if e, ok := err.(*ParseError); ok {
+		if e.ID == "" {
+			e.ID = parent
+		} else {
+			e.ID = parent + "." + e.ID
+		}
+	}
+	return err
+}
+
+func digits(o DataObject, n int) (string, error) {
+	if len(o.Value) != n || !isDigits(o.Value) {
+		return "", &ParseError{o.Offset + 4, o.ID, fmt.Sprintf("want %d digits, got %q", n, o.Value)}
+	}
+	return o.Value, nil
+}
+
+/** decimal amount with '.' as separator, eg "10", "10.5" or "0.99". */
+func amount(o DataObject) (string, error) {
+	var whole, frac, dot = strings.Cut(o.Value, ".")
+	if !isDigits(whole) || dot && !isDigits(frac) {
+		return "", &ParseError{o.Offset + 4, o.ID, fmt.Sprintf("invalid amount %q", o.Value)}
+	}
+	return o.Value, nil
+}
+
+func isAlpha(s string) bool {
+	for _, c := range s {
+		if (c < 'A' || c > 'Z') && (c < 'a' || c > 'z') {
+			return false
+		}
+	}
+	return true
+}
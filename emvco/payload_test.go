@@ -0,0 +1,79 @@
+package emvco
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+/** EMV QRCPS MPM specification example. */
+const example = "00020101021229300012D156000000000510A93FO3230Q31280012D15600000001030812345678520441115802CN5914BEST TRANSPORT6007BEIJING64200002ZH0104最佳运输0202北京540523.7253031565502016233030412340603***0708A60086670902ME91320016A0112233449988770708123456786304A13A"
+
+/** append a valid CRC to a payload. */
+func withCRC(s string) string {
+	s += "6304"
+	return s + fmt.Sprintf("%04X", CRC16([]byte(s)))
+}
+
+func TestParseExample(t *testing.T) {
+	p, err := Parse(example)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Dynamic || p.CategoryCode != "4111" || p.Currency != "156" || p.Amount != "23.72" ||
+		p.Tip != TipPrompt || p.Country != "CN" || p.MerchantName != "BEST TRANSPORT" ||
+		p.MerchantCity != "BEIJING" || p.CRC != 0xA13A {
+		t.Errorf("got %+v", p)
+	}
+	if len(p.MerchantAccounts) != 2 || p.MerchantAccounts[0].GUID != "D15600000000" ||
+		p.MerchantAccounts[0].Get("05") != "A93FO3230Q" || p.MerchantAccounts[1].Get("03") != "12345678" {
+		t.Errorf("merchant accounts %+v", p.MerchantAccounts)
+	}
+	if l := p.Language; l == nil || l.Language != "ZH" || l.Name != "最佳运输" || l.City != "北京" {
+		t.Errorf("language %+v", l)
+	}
+	if a := p.Additional; a == nil || a.StoreLabel != "1234" || a.CustomerLabel != "***" ||
+		a.TerminalLabel != "A6008667" || a.ConsumerDataRequest != "ME" {
+		t.Errorf("additional %+v", a)
+	}
+	if len(p.Unreserved) != 1 || p.Unreserved[0].GUID != "A011223344998877" || p.Unreserved[0].Get("07") != "12345678" {
+		t.Errorf("unreserved %+v", p.Unreserved)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	const base = "000201" + "0216" + "4111111111111111" + "52045812" + "5303840" + "5802US" + "5905SHOP1" + "6004CITY"
+	if p, err := Parse(withCRC(base)); err != nil || p.Dynamic || p.MerchantAccounts[0].Network() != "Visa" {
+		t.Fatalf("base: %+v %v", p, err)
+	}
+
+	var bad = example[:len(example)-4] + "A13B"
+	_, err := Parse(bad)
+	if e, ok := err.(*ParseError); !ok || e.ID != "63" || !strings.Contains(e.Msg, "mismatch") {
+		t.Errorf("crc: %v", err)
+	}
+
+	for name, tc := range map[string]struct {
+		payload string
+		id      string
+		offset  int
+	}{
+		"no format":      {withCRC("0102115204581253038405802US"), "01", 0},
+		"initiation":     {withCRC(strings.Replace(base, "000201", "0002010102X3", 1)), "01", 10},
+		"no account":     {withCRC("000201" + "52045812" + "5303840" + "5802US" + "5905SHOP1" + "6004CITY"), "", 44},
+		"mcc":            {withCRC(strings.Replace(base, "52045812", "520458A2", 1)), "52", 30},
+		"amount":         {withCRC(base + "54041.2."), "54", 68},
+		"duplicate":      {withCRC(base + "6004CITY"), "60", 64},
+		"template guid":  {withCRC(base + "26090105ABCDE"), "26.00", 68},
+		"nested length":  {withCRC(base + "62080510ABCD"), "62.05", 70},
+		"fee without 55": {withCRC(base + "56031.5"), "56", 64},
+		"fee missing":    {withCRC(base + "550202"), "56", 70},
+		"country":        {withCRC(strings.Replace(base, "5802US", "5802us", 1)), "58", 45},
+		"no crc":         {base, "60", 56},
+	} {
+		_, err := Parse(tc.payload)
+		if e, ok := err.(*ParseError); !ok || e.ID != tc.id || e.Offset != tc.offset {
+			t.Errorf("%s: %v, want ID %q at %d", name, err, tc.id, tc.offset)
+		}
+	}
+}
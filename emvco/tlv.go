@@ -0,0 +1,101 @@
+/** Package emvco parses EMVCo merchant-presented mode (MPM) QR code
+ * payment payloads.
+ *
+ * the payload is a sequence of data objects, each a 2 digit ID, a 2
+ * digit length and a value of that many characters.  some values are
+ * templates holding nested data objects in the same format.  the
+ * last object (ID "63") is a CRC16-CCITT over everything before its
+ * value.  Parse() validates the structure and the CRC and returns a
+ * typed Payload; errors report the character offset at fault.
+ */
+package emvco
+
+import (
+	"fmt"
+	"strconv"
+)
+
+/** invalid payload. */
+type ParseError struct {
+	Offset int    // character offset into the payload
+	ID     string // ID of the offending data object, "" if unknown
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	if e.ID == "" {
+		return fmt.Sprintf("emvco: offset %d: %s", e.Offset, e.Msg)
+	}
+	return fmt.Sprintf("emvco: offset %d: ID %s: %s", e.Offset, e.ID, e.Msg)
+}
+
+/** ID, length, value encoded data object.
+ * Offset is the character offset of the ID in the payload.
+ */
+type DataObject struct {
+	ID     string
+	Value  string
+	Offset int
+}
+
+/** numeric ID. */
+func (o DataObject) Num() int {
+	var n, _ = strconv.Atoi(o.ID)
+	return n
+}
+
+/** split value into data objects.
+ * lengths count characters, not bytes; base is the offset of value
+ * in the payload.
+ */
+func Split(value []rune, base int) ([]DataObject, error) {
+	var objects []DataObject
+	for i := 0; i < len(value); {
+		if len(value)-i < 4 {
+			return nil, &ParseError{base + i, "", "truncated data object header"}
+		}
+		var id = string(value[i : i+2])
+		if !isDigits(id) {
+			return nil, &ParseError{base + i, "", fmt.Sprintf("invalid ID %q", id)}
+		}
+		var length = string(value[i+2 : i+4])
+		if !isDigits(length) {
+			return nil, &ParseError{base + i + 2, id, fmt.Sprintf("invalid length %q", length)}
+		}
+		var n, _ = strconv.Atoi(length)
+		if n == 0 {
+			return nil, &ParseError{base + i + 2, id, "empty value"}
+		}
+		if i+4+n > len(value) {
+			return nil, &ParseError{base + i + 2, id, fmt.Sprintf("length %d exceeds remaining %d characters", n, len(value)-i-4)}
+		}
+		objects = append(objects, DataObject{ID: id, Value: string(value[i+4 : i+4+n]), Offset: base + i})
+		i += 4 + n
+	}
+	return objects, nil
+}
+
+/** CRC-16/CCITT-FALSE (polynomial 0x1021, initial value 0xFFFF). */
+func CRC16(data []byte) uint16 {
+	var crc uint16 = 0xFFFF
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,33 @@
+package emvco
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCRC16(t *testing.T) {
+	if crc := CRC16([]byte("123456789")); crc != 0x29B1 {
+		t.Errorf("crc = %04X, want 29B1", crc)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	objects, err := Split([]rune("0002010102最佳"), 10)
+	var want = []DataObject{{"00", "01", 10}, {"01", "最佳", 16}}
+	if err != nil || !reflect.DeepEqual(objects, want) {
+		t.Errorf("got %v %v", objects, err)
+	}
+
+	for data, offset := range map[string]int{
+		"000201X":    16, // truncated header
+		"0002010A01": 16, // invalid ID
+		"00A101":     12, // invalid length
+		"000001":     12, // empty value
+		"000501":     12, // overflow
+	} {
+		_, err := Split([]rune(data), 10)
+		if e, ok := err.(*ParseError); !ok || e.Offset != offset {
+			t.Errorf("%s: %v, want error at %d", data, err, offset)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package zbar
+
+import (
+	"strings"
+
+	"github.com/zooyer/zbar/gs1"
+)
+
+/*------------------------------------------------------------*/
+/** @name GS1 element strings
+ * Application Identifier data of GS1-128 (Code 128 with FNC1),
+ * DataBar and GS1 QR codes, including GS1 Digital Link URIs, see
+ * package gs1
+ */
+/*@{*/
+
+/** parse the symbol data as a GS1 element string.
+ * when built for libzbar 0.11 and later (zbar011 tag) symbols without
+ * the ZBAR_MOD_GS1 modifier are rejected with gs1.ErrNotGS1, except
+ * for GS1 Digital Link URIs, which are plain URLs.  older libraries
+ * do not report the modifier, so any data that parses is accepted.
+ */
+func (sym Symbol) GS1() (*gs1.Message, error) {
+	if haveModifiers && !sym.HasModifier(ZBAR_MOD_GS1) && !isURI(sym.Data) {
+		return nil, gs1.ErrNotGS1
+	}
+	return gs1.Parse(sym.Data)
+}
+
+/** check for an http(s) URI, as used by GS1 Digital Link. */
+func isURI(data string) bool {
+	var lower = strings.ToLower(data)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+/*@}*/
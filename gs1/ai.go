@@ -0,0 +1,153 @@
+package gs1
+
+/*------------------------------------------------------------*/
+/** @name Application Identifier table
+ * the AIs in common use on logistics and retail labels, from the GS1
+ * General Specifications.  AIs of the form "310n" take the number of
+ * decimal places (or a similar indicator) as their last digit and
+ * are listed by their 3 digit prefix
+ */
+/*@{*/
+
+/** data format of an AI. */
+type Kind int
+
+const (
+	Text     Kind = iota /**< GS1 AI encodable character set 82 */
+	Numeric              /**< digits only */
+	Date                 /**< YYMMDD, a day of 00 means the end of the month */
+	DateTime             /**< YYMMDDHHMM */
+	Decimal              /**< digits, decimal places given by the last AI digit */
+	Currency             /**< ISO 4217 numeric code followed by a decimal amount */
+)
+
+/** Application Identifier definition. */
+type AI struct {
+	Code   string // AI, or its 3 digit prefix for "310n" style AIs
+	Title  string // data title, eg "BATCH/LOT"
+	Kind   Kind
+	Min    int  // minimum data length
+	Max    int  // maximum data length
+	Check  int  // number of leading digits ending in a mod 10 check digit, or 0
+	Digits int  // length of the AI itself
+	Fixed  bool // predefined length: no FNC1 separator follows the data
+}
+
+/** define an AI; Decimal and Currency AIs get an indicator digit. */
+func ai(code, title string, kind Kind, min, max, check int) *AI {
+	var digits = len(code)
+	if kind == Decimal || kind == Currency {
+		digits++
+	}
+	return &AI{Code: code, Title: title, Kind: kind, Min: min, Max: max, Check: check, Digits: digits}
+}
+
+/** AI table, indexed by code. */
+var table = map[string]*AI{}
+
+/** AIs with predefined lengths (GS1 General Specifications 7.8.5),
+ * by their first two digits.
+ */
+var predefined = map[string]bool{
+	"00": true, "01": true, "02": true, "03": true, "04": true,
+	"11": true, "12": true, "13": true, "14": true, "15": true,
+	"16": true, "17": true, "18": true, "19": true, "20": true,
+	"31": true, "32": true, "33": true, "34": true, "35": true,
+	"36": true, "41": true,
+}
+
+func init() {
+	for _, def := range []*AI{
+		ai("00", "SSCC", Numeric, 18, 18, 18),
+		ai("01", "GTIN", Numeric, 14, 14, 14),
+		ai("02", "CONTENT", Numeric, 14, 14, 14),
+		ai("10", "BATCH/LOT", Text, 1, 20, 0),
+		ai("11", "PROD DATE", Date, 6, 6, 0),
+		ai("12", "DUE DATE", Date, 6, 6, 0),
+		ai("13", "PACK DATE", Date, 6, 6, 0),
+		ai("15", "BEST BEFORE or BEST BY", Date, 6, 6, 0),
+		ai("16", "SELL BY", Date, 6, 6, 0),
+		ai("17", "USE BY or EXPIRY", Date, 6, 6, 0),
+		ai("20", "VARIANT", Numeric, 2, 2, 0),
+		ai("21", "SERIAL", Text, 1, 20, 0),
+		ai("22", "CPV", Text, 1, 20, 0),
+		ai("235", "TPX", Text, 1, 28, 0),
+		ai("240", "ADDITIONAL ID", Text, 1, 30, 0),
+		ai("241", "CUST. PART No.", Text, 1, 30, 0),
+		ai("250", "SECONDARY SERIAL", Text, 1, 30, 0),
+		ai("251", "REF. TO SOURCE", Text, 1, 30, 0),
+		ai("253", "GDTI", Text, 13, 30, 13),
+		ai("254", "GLN EXTENSION COMPONENT", Text, 1, 20, 0),
+		ai("255", "GCN", Numeric, 13, 25, 13),
+		ai("30", "VAR. COUNT", Numeric, 1, 8, 0),
+		ai("310", "NET WEIGHT (kg)", Decimal, 6, 6, 0),
+		ai("311", "LENGTH (m)", Decimal, 6, 6, 0),
+		ai("312", "WIDTH (m)", Decimal, 6, 6, 0),
+		ai("313", "HEIGHT (m)", Decimal, 6, 6, 0),
+		ai("314", "AREA (m²)", Decimal, 6, 6, 0),
+		ai("315", "NET VOLUME (l)", Decimal, 6, 6, 0),
+		ai("316", "NET VOLUME (m³)", Decimal, 6, 6, 0),
+		ai("320", "NET WEIGHT (lb)", Decimal, 6, 6, 0),
+		ai("330", "GROSS WEIGHT (kg)", Decimal, 6, 6, 0),
+		ai("331", "LENGTH (m), log", Decimal, 6, 6, 0),
+		ai("332", "WIDTH (m), log", Decimal, 6, 6, 0),
+		ai("333", "HEIGHT (m), log", Decimal, 6, 6, 0),
+		ai("334", "AREA (m²), log", Decimal, 6, 6, 0),
+		ai("335", "VOLUME (l), log", Decimal, 6, 6, 0),
+		ai("336", "VOLUME (m³), log", Decimal, 6, 6, 0),
+		ai("37", "COUNT", Numeric, 1, 8, 0),
+		ai("390", "AMOUNT", Decimal, 1, 15, 0),
+		ai("391", "AMOUNT", Currency, 4, 18, 0),
+		ai("392", "PRICE", Decimal, 1, 15, 0),
+		ai("393", "PRICE", Currency, 4, 18, 0),
+		ai("400", "ORDER NUMBER", Text, 1, 30, 0),
+		ai("401", "GINC", Text, 1, 30, 0),
+		ai("402", "GSIN", Numeric, 17, 17, 17),
+		ai("403", "ROUTE", Text, 1, 30, 0),
+		ai("410", "SHIP TO LOC", Numeric, 13, 13, 13),
+		ai("411", "BILL TO", Numeric, 13, 13, 13),
+		ai("412", "PURCHASE FROM", Numeric, 13, 13, 13),
+		ai("413", "SHIP FOR LOC", Numeric, 13, 13, 13),
+		ai("414", "LOC No.", Numeric, 13, 13, 13),
+		ai("415", "PAY TO", Numeric, 13, 13, 13),
+		ai("416", "PROD/SERV LOC", Numeric, 13, 13, 13),
+		ai("420", "SHIP TO POST", Text, 1, 20, 0),
+		ai("421", "SHIP TO POST", Text, 4, 12, 0),
+		ai("422", "ORIGIN", Numeric, 3, 3, 0),
+		ai("7003", "EXPIRY TIME", DateTime, 10, 10, 0),
+		ai("8003", "GRAI", Text, 14, 30, 14),
+		ai("8004", "GIAI", Text, 1, 30, 0),
+		ai("8006", "ITIP", Numeric, 18, 18, 14),
+		ai("8017", "GSRN - PROVIDER", Numeric, 18, 18, 18),
+		ai("8018", "GSRN - RECIPIENT", Numeric, 18, 18, 18),
+		ai("8020", "REF No.", Text, 1, 25, 0),
+		ai("90", "INTERNAL", Text, 1, 30, 0),
+	} {
+		table[def.Code] = def
+	}
+	for _, code := range []string{"91", "92", "93", "94", "95", "96", "97", "98", "99"} {
+		table[code] = ai(code, "INTERNAL", Text, 1, 90, 0)
+	}
+	for code, def := range table {
+		def.Fixed = predefined[code[:2]]
+	}
+}
+
+/** look up the AI at the start of data.
+ * AIs are prefix free, so at most one of the 2, 3 and 4 digit
+ * prefixes of data is defined.
+ * @returns the definition and the AI itself, eg "3103"
+ */
+func Lookup(data string) (*AI, string) {
+	for n := 2; n <= 4 && n <= len(data); n++ {
+		if def, ok := table[data[:n]]; ok {
+			if len(data) < def.Digits || !isDigits(data[n:def.Digits]) {
+				return nil, ""
+			}
+			return def, data[:def.Digits]
+		}
+	}
+	return nil, ""
+}
+
+/*@}*/
@@ -0,0 +1,165 @@
+package gs1
+
+import (
+	"net/url"
+	"strings"
+)
+
+/*------------------------------------------------------------*/
+/** @name GS1 Digital Link
+ * web URIs carrying AIs, eg
+ * https://id.gs1.org/01/09501101530003/10/AB-123?17=250630:
+ * a primary key AI and its qualifiers as path segments, attributes
+ * as query parameters
+ */
+/*@{*/
+
+/** primary key AIs and the qualifier AIs that may follow them in
+ * the path, in their defined order.
+ */
+var primaryKeys = map[string][]string{
+	"01":   {"22", "10", "21"},
+	"00":   nil,
+	"253":  nil,
+	"255":  nil,
+	"401":  nil,
+	"402":  nil,
+	"414":  {"254"},
+	"8003": nil,
+	"8004": nil,
+	"8006": {"22", "10", "21"},
+	"8017": nil,
+	"8018": nil,
+}
+
+/** short names accepted in place of numeric AIs. */
+var shortNames = map[string]string{
+	"sscc": "00", "gtin": "01", "cpv": "22", "lot": "10", "ser": "21",
+	"gdti": "253", "gcn": "255", "ginc": "401", "gsin": "402",
+	"gln": "414", "glnx": "254", "grai": "8003", "giai": "8004",
+	"itip": "8006", "gsrnp": "8017", "gsrn": "8018", "exp": "17",
+}
+
+/** resolve a path segment or query key to an AI. */
+func dlKey(key string) string {
+	if ai, ok := shortNames[key]; ok {
+		return ai
+	}
+	return key
+}
+
+/** parse a GS1 Digital Link URI.
+ * the path may start with any number of other segments (eg a brand's
+ * own prefix) before the primary key.  GTINs shorter than 14 digits
+ * are padded with zeros.  query parameters that are not AIs (eg
+ * linkType) are ignored.
+ */
+func ParseDigitalLink(uri string) (*Message, error) {
+	u, err := url.Parse(uri)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, ErrNotGS1
+	}
+
+	var segments = strings.Split(strings.Trim(u.EscapedPath(), "/"), "/")
+	for i := range segments {
+		if segments[i], err = url.PathUnescape(segments[i]); err != nil {
+			return nil, &ParseError{Msg: "invalid path escape"}
+		}
+	}
+
+	// the primary key is the first segment that is a primary key AI
+	// followed only by its value and qualifier pairs
+	var start = -1
+	for i := 0; i+1 < len(segments) && start < 0; i++ {
+		if qualifiers, ok := primaryKeys[dlKey(segments[i])]; ok && (len(segments)-i)%2 == 0 && qualifiersOK(segments[i+2:], qualifiers) {
+			start = i
+		}
+	}
+	if start < 0 {
+		return nil, ErrNotGS1
+	}
+
+	var m = &Message{}
+	var add = func(key, value string) *ParseError {
+		var ai = dlKey(key)
+		def, code := Lookup(ai)
+		if def == nil || code != ai {
+			return &ParseError{AI: key, Msg: "unknown AI"}
+		}
+		if ai == "01" && len(value) < 14 && isDigits(value) {
+			value = strings.Repeat("0", 14-len(value)) + value
+		}
+		var e = Element{AI: ai, Def: def, Data: value}
+		if err := e.validate(); err != nil {
+			return err
+		}
+		m.Elements = append(m.Elements, e)
+		return nil
+	}
+
+	for i := start; i < len(segments); i += 2 {
+		if err := add(segments[i], segments[i+1]); err != nil {
+			return nil, err
+		}
+	}
+	for _, param := range strings.Split(u.RawQuery, "&") {
+		key, value, _ := strings.Cut(param, "=")
+		if key = dlKey(key); key == "" || !isDigits(key) {
+			continue
+		}
+		if value, err = url.QueryUnescape(value); err != nil {
+			return nil, &ParseError{AI: key, Msg: "invalid query escape"}
+		}
+		if err := add(key, value); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+/** check that the path pairs after a primary key are qualifiers in
+ * the defined order.
+ */
+func qualifiersOK(pairs []string, qualifiers []string) bool {
+	var next = 0
+	for i := 0; i < len(pairs); i += 2 {
+		var ai = dlKey(pairs[i])
+		for next < len(qualifiers) && qualifiers[next] != ai {
+			next++
+		}
+		if next == len(qualifiers) {
+			return false
+		}
+		next++
+	}
+	return true
+}
+
+/** encode as an element string as carried by GS1-128: AIs followed
+ * by their data, with a GS separator after variable length data
+ * unless it is the last element.
+ */
+func (m *Message) ElementString() string {
+	var b strings.Builder
+	for i, e := range m.Elements {
+		b.WriteString(e.AI)
+		b.WriteString(e.Data)
+		if i < len(m.Elements)-1 && (e.Def == nil || !e.Def.Fixed) {
+			b.WriteString(GS)
+		}
+	}
+	return b.String()
+}
+
+/** human readable form with parenthesized AIs, eg
+ * "(01)09501101530003(10)AB-123".
+ */
+func (m *Message) String() string {
+	var b strings.Builder
+	for _, e := range m.Elements {
+		b.WriteString("(" + e.AI + ")" + e.Data)
+	}
+	return b.String()
+}
+
+/*@}*/
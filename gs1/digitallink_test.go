@@ -0,0 +1,46 @@
+package gs1
+
+import "testing"
+
+func TestParseDigitalLink(t *testing.T) {
+	for _, uri := range []string{
+		"https://id.gs1.org/01/09501101530003/10/AB-123/21/S%2FN?17=250600&3103=001250&linkType=gs1:pip",
+		"https://example.com/products/gtin/9501101530003/lot/AB-123/ser/S%2FN?exp=250600&3103=001250",
+	} {
+		m, err := Parse(uri)
+		if err != nil {
+			t.Fatalf("%s: %v", uri, err)
+		}
+		if s := m.String(); s != "(01)09501101530003(10)AB-123(21)S/N(17)250600(3103)001250" {
+			t.Errorf("%s: %s", uri, s)
+		}
+		if s := m.ElementString(); s != "0109501101530003"+"10AB-123"+GS+"21S/N"+GS+"17250600"+"3103001250" {
+			t.Errorf("%s: element string %q", uri, s)
+		}
+		if w, _ := m.NetWeight(); w != 1.25 {
+			t.Errorf("net weight = %v", w)
+		}
+	}
+
+	m, err := Parse("HTTPS://ID.GS1.ORG/01/09501101530003/10/AB-123")
+	if err != nil || m.GTIN() != "09501101530003" || m.Batch() != "AB-123" {
+		t.Errorf("upper case: %v %v", m, err)
+	}
+
+	// element strings round trip
+	var data = "0109501101530003" + "10AB-123" + GS + "21S/N"
+	if m, err := Parse(data); err != nil || m.ElementString() != data {
+		t.Errorf("round trip: %v", err)
+	}
+
+	for uri, want := range map[string]string{
+		"https://id.gs1.org/01/09501101530004":           "gs1: AI (01) at offset 0: invalid check digit",
+		"https://id.gs1.org/01/09501101530003/21/1/10/A": "gs1: not a GS1 element string",
+		"https://example.com/about":                      "gs1: not a GS1 element string",
+		"ftp://id.gs1.org/01/09501101530003":             "gs1: not a GS1 element string",
+	} {
+		if _, err := ParseDigitalLink(uri); err == nil || err.Error() != want {
+			t.Errorf("%s: %v, want %s", uri, err, want)
+		}
+	}
+}
@@ -0,0 +1,385 @@
+/** Package gs1 parses GS1 element strings, the Application Identifier
+ * (AI) encoded data carried by GS1-128, GS1 DataBar, GS1 DataMatrix
+ * and GS1 QR Code symbols.
+ *
+ * element strings are accepted as decoded from the symbol (FNC1
+ * transmitted as the GS character, optionally preceded by an AIM
+ * symbology identifier such as "]C1"), in the human readable form
+ * with parenthesized AIs, or as a GS1 Digital Link URI.
+ */
+package gs1
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/** group separator, transmitted for FNC1 after variable length data. */
+const GS = "\x1d"
+
+/** returned for data that does not start with an AI. */
+var ErrNotGS1 = errors.New("gs1: not a GS1 element string")
+
+/** invalid element string. */
+type ParseError struct {
+	AI     string // AI of the offending element, if known
+	Offset int    // byte offset into the data
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	if e.AI == "" {
+		return fmt.Sprintf("gs1: offset %d: %s", e.Offset, e.Msg)
+	}
+	return fmt.Sprintf("gs1: AI (%s) at offset %d: %s", e.AI, e.Offset, e.Msg)
+}
+
+/** one AI and its data. */
+type Element struct {
+	AI   string // eg "01" or "3103"
+	Def  *AI
+	Data string
+}
+
+/** data as a date (Date and DateTime AIs), in UTC.
+ * the century is chosen as in GS1 General Specifications 7.12: the
+ * year lies between 49 years before and 50 years after the current
+ * one.  a day of 00 denotes the last day of the month.
+ */
+func (e Element) Time() (time.Time, error) {
+	if e.Def == nil || (e.Def.Kind != Date && e.Def.Kind != DateTime) {
+		return time.Time{}, fmt.Errorf("gs1: AI (%s) is not a date", e.AI)
+	}
+	return parseDate(e.Data)
+}
+
+/** data as a number, with the decimal point placed as given by the
+ * last AI digit (Decimal and Currency AIs; see Currency() for the
+ * latter's currency code).
+ */
+func (e Element) Decimal() (float64, error) {
+	if e.Def == nil || (e.Def.Kind != Decimal && e.Def.Kind != Currency) {
+		return 0, fmt.Errorf("gs1: AI (%s) is not a decimal", e.AI)
+	}
+	var digits = e.Data
+	if e.Def.Kind == Currency {
+		digits = digits[3:]
+	}
+	v, err := strconv.ParseUint(digits, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	var places = int(e.AI[len(e.AI)-1] - '0')
+	return float64(v) / math.Pow10(places), nil
+}
+
+/** ISO 4217 numeric currency code of Currency AIs, eg "978". */
+func (e Element) Currency() string {
+	if e.Def == nil || e.Def.Kind != Currency {
+		return ""
+	}
+	return e.Data[:3]
+}
+
+/** parsed element string. */
+type Message struct {
+	Elements []Element
+}
+
+/** first element with the given AI. */
+func (m *Message) Get(ai string) (Element, bool) {
+	for _, e := range m.Elements {
+		if e.AI == ai {
+			return e, true
+		}
+	}
+	return Element{}, false
+}
+
+/** data of the first element with the given AI, or "". */
+func (m *Message) Value(ai string) string {
+	e, _ := m.Get(ai)
+	return e.Data
+}
+
+/** SSCC (00). */
+func (m *Message) SSCC() string { return m.Value("00") }
+
+/** GTIN (01). */
+func (m *Message) GTIN() string { return m.Value("01") }
+
+/** batch or lot number (10). */
+func (m *Message) Batch() string { return m.Value("10") }
+
+/** serial number (21). */
+func (m *Message) Serial() string { return m.Value("21") }
+
+/** expiry date (17), zero if absent. */
+func (m *Message) Expiry() time.Time {
+	e, ok := m.Get("17")
+	if !ok {
+		return time.Time{}
+	}
+	t, _ := e.Time()
+	return t
+}
+
+/** net weight in kg (310n).
+ * @returns false if the message has no net weight
+ */
+func (m *Message) NetWeight() (float64, bool) {
+	for _, e := range m.Elements {
+		if strings.HasPrefix(e.AI, "310") {
+			v, err := e.Decimal()
+			return v, err == nil
+		}
+	}
+	return 0, false
+}
+
+/** report whether data starts with an http or https scheme, in any
+ * case: QR codes in alphanumeric mode hold upper case URIs.
+ */
+func isURI(data string) bool {
+	var scheme = strings.ToLower(data[:min(len(data), len("https://"))])
+	return strings.HasPrefix(scheme, "http://") || scheme == "https://"
+}
+
+/** AIM symbology identifiers of GS1 symbols. */
+var identifiers = []string{"]C1", "]e0", "]d2", "]Q3", "]J1"}
+
+/** parse an element string.
+ * every element is validated against the AI table: length, character
+ * set, check digit and dates.  http and https URIs are parsed with
+ * ParseDigitalLink().
+ */
+func Parse(data string) (*Message, error) {
+	if isURI(data) {
+		return ParseDigitalLink(data)
+	}
+	for _, id := range identifiers {
+		if strings.HasPrefix(data, id) {
+			data = data[len(id):]
+			break
+		}
+	}
+	if strings.HasPrefix(data, "(") {
+		return parseBracketed(data)
+	}
+
+	var m = &Message{}
+	var pos = 0
+	for pos < len(data) && data[pos:pos+1] == GS {
+		pos++
+	}
+	if pos == len(data) {
+		return nil, ErrNotGS1
+	}
+
+	for pos < len(data) {
+		def, code := Lookup(data[pos:])
+		if def == nil {
+			if len(m.Elements) == 0 {
+				return nil, ErrNotGS1
+			}
+			return nil, &ParseError{Offset: pos, Msg: "unknown AI"}
+		}
+
+		var start = pos + len(code)
+		var end int
+		if def.Min == def.Max {
+			if end = start + def.Max; end > len(data) {
+				return nil, &ParseError{AI: code, Offset: pos, Msg: "data too short"}
+			}
+		} else if end = strings.Index(data[start:], GS); end < 0 {
+			end = len(data)
+		} else {
+			end += start
+		}
+
+		var e = Element{AI: code, Def: def, Data: data[start:end]}
+		if err := e.validate(); err != nil {
+			err.Offset = pos
+			return nil, err
+		}
+		m.Elements = append(m.Elements, e)
+
+		if pos = end; pos < len(data) && data[pos:pos+1] == GS {
+			pos++
+		}
+	}
+	return m, nil
+}
+
+/** parse the human readable form, eg "(01)09501101530003(10)AB12". */
+func parseBracketed(data string) (*Message, error) {
+	var m = &Message{}
+	for pos := 0; pos < len(data); {
+		var code, n = bracketedAI(data[pos:])
+		if n == 0 {
+			return nil, &ParseError{Offset: pos, Msg: "expected (AI)"}
+		}
+
+		// data may itself contain parentheses, so it extends to the
+		// next parenthesized known AI
+		var start = pos + n
+		var end = start
+		for end < len(data) {
+			if _, n := bracketedAI(data[end:]); n > 0 {
+				break
+			}
+			end++
+		}
+
+		var def, _ = Lookup(code)
+		var e = Element{AI: code, Def: def, Data: data[start:end]}
+		if err := e.validate(); err != nil {
+			err.Offset = pos
+			return nil, err
+		}
+		m.Elements = append(m.Elements, e)
+		pos = end
+	}
+	if len(m.Elements) == 0 {
+		return nil, ErrNotGS1
+	}
+	return m, nil
+}
+
+/** known AI in parentheses at the start of s.
+ * @returns the AI and the length of the parenthesized AI, 0 if none
+ */
+func bracketedAI(s string) (string, int) {
+	var close = strings.IndexByte(s, ')')
+	if len(s) == 0 || s[0] != '(' || close < 3 || close > 5 {
+		return "", 0
+	}
+	var code = s[1:close]
+	if def, ai := Lookup(code); def == nil || ai != code {
+		return "", 0
+	}
+	return code, close + 1
+}
+
+/** check an element against its AI definition. */
+func (e Element) validate() *ParseError {
+	var def = e.Def
+	var fail = func(format string, args ...interface{}) *ParseError {
+		return &ParseError{AI: e.AI, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	if n := len(e.Data); n < def.Min || n > def.Max {
+		if def.Min == def.Max {
+			return fail("length %d, want %d", n, def.Max)
+		}
+		return fail("length %d, want %d to %d", n, def.Min, def.Max)
+	}
+
+	switch def.Kind {
+	case Text:
+		for i := 0; i < len(e.Data); i++ {
+			if !isCSet82(e.Data[i]) {
+				return fail("invalid character %q", e.Data[i])
+			}
+		}
+		if def.Check > 0 && !isDigits(e.Data[:def.Check]) {
+			return fail("non-numeric data")
+		}
+	default:
+		if !isDigits(e.Data) {
+			return fail("non-numeric data")
+		}
+	}
+
+	if def.Check > 0 && !CheckDigit(e.Data[:def.Check]) {
+		return fail("invalid check digit")
+	}
+	if def.Kind == Date || def.Kind == DateTime {
+		if _, err := parseDate(e.Data); err != nil {
+			return fail("invalid date")
+		}
+	}
+	return nil
+}
+
+/** check the GS1 mod 10 check digit ending a digit string. */
+func CheckDigit(digits string) bool {
+	if len(digits) < 2 || !isDigits(digits) {
+		return false
+	}
+	return ComputeCheckDigit(digits[:len(digits)-1]) == digits[len(digits)-1]
+}
+
+/** compute the GS1 mod 10 check digit for a digit string: weights 3
+ * and 1 alternate from the rightmost digit.
+ */
+func ComputeCheckDigit(digits string) byte {
+	var sum int
+	for i := len(digits) - 1; i >= 0; i-- {
+		var d = int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+/** current time, replaced by tests. */
+var now = time.Now
+
+/** parse YYMMDD or YYMMDDHHMM. */
+func parseDate(s string) (time.Time, error) {
+	if (len(s) != 6 && len(s) != 10) || !isDigits(s) {
+		return time.Time{}, fmt.Errorf("gs1: invalid date %q", s)
+	}
+	var num = func(i int) int { return int(s[i]-'0')*10 + int(s[i+1]-'0') }
+
+	var current = now().UTC().Year()
+	var year = current - current%100 + num(0)
+	switch diff := year - current; {
+	case diff > 50:
+		year -= 100
+	case diff < -49:
+		year += 100
+	}
+
+	var month, day = num(2), num(4)
+	var hour, minute int
+	if len(s) == 10 {
+		hour, minute = num(6), num(8)
+	}
+	if month < 1 || month > 12 || hour > 23 || minute > 59 {
+		return time.Time{}, fmt.Errorf("gs1: invalid date %q", s)
+	}
+	var last = time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day == 0 && len(s) == 6 {
+		day = last
+	}
+	if day < 1 || day > last {
+		return time.Time{}, fmt.Errorf("gs1: invalid date %q", s)
+	}
+	return time.Date(year, time.Month(month), day, hour, minute, 0, 0, time.UTC), nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+/** GS1 AI encodable character set 82. */
+func isCSet82(c byte) bool {
+	switch {
+	case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+		return true
+	}
+	return strings.IndexByte("!\"%&'()*+,-./:;<=>?_", c) >= 0
+}
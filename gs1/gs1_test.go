@@ -0,0 +1,85 @@
+package gs1
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func init() {
+	now = func() time.Time { return time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC) }
+}
+
+func TestParse(t *testing.T) {
+	for _, data := range []string{
+		"]C10109501101530003172506001" + "0AB-123" + GS + "3103001250" + "21S/N" + GS + "00106141411234567897",
+		GS + "0109501101530003172506001" + "0AB-123" + GS + "3103001250" + "21S/N" + GS + "00106141411234567897",
+		"(01)09501101530003(17)250600(10)AB-123(3103)001250(21)S/N(00)106141411234567897",
+	} {
+		m, err := Parse(data)
+		if err != nil {
+			t.Fatalf("%q: %v", data, err)
+		}
+		if len(m.Elements) != 6 {
+			t.Fatalf("%q: %d elements", data, len(m.Elements))
+		}
+		if m.GTIN() != "09501101530003" || m.Batch() != "AB-123" || m.Serial() != "S/N" || m.SSCC() != "106141411234567897" {
+			t.Errorf("%q: %+v", data, m.Elements)
+		}
+		if exp := m.Expiry(); !exp.Equal(time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("expiry = %v", exp)
+		}
+		if w, ok := m.NetWeight(); !ok || w != 1.25 {
+			t.Errorf("net weight = %v %v", w, ok)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	var cases = map[string]string{
+		"0109501101530004":         "gs1: AI (01) at offset 0: invalid check digit",
+		"010950110153000":          "gs1: AI (01) at offset 0: data too short",
+		"010950110153000317251332": "gs1: AI (17) at offset 16: invalid date",
+		"0109501101530003" + "10" + "ABCDEFGHIJKLMNOPQRSTU": "gs1: AI (10) at offset 16: length 21, want 1 to 20",
+		"0109501101530003" + "10A#":                         "gs1: AI (10) at offset 16: invalid character '#'",
+		"0109501101530003" + "23X":                          "gs1: offset 16: unknown AI",
+	}
+	for data, want := range cases {
+		_, err := Parse(data)
+		if err == nil || err.Error() != want {
+			t.Errorf("%q: %v, want %s", data, err, want)
+		}
+	}
+
+	for _, data := range []string{"", "hello", "]C1"} {
+		if _, err := Parse(data); !errors.Is(err, ErrNotGS1) {
+			t.Errorf("%q: %v", data, err)
+		}
+	}
+}
+
+func TestElement(t *testing.T) {
+	m, err := Parse("(3922)12345(3912)978995(7003)2412312359")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := m.Elements[0].Decimal(); v != 123.45 {
+		t.Errorf("price = %v", v)
+	}
+	if v, _ := m.Elements[1].Decimal(); v != 9.95 || m.Elements[1].Currency() != "978" {
+		t.Errorf("amount = %v %s", v, m.Elements[1].Currency())
+	}
+	if ts, _ := m.Elements[2].Time(); !ts.Equal(time.Date(2024, 12, 31, 23, 59, 0, 0, time.UTC)) {
+		t.Errorf("expiry time = %v", ts)
+	}
+
+	// century window around the current year (2024)
+	for s, year := range map[string]int{"740101": 2074, "750101": 1975, "000101": 2000} {
+		if ts, _ := parseDate(s); ts.Year() != year {
+			t.Errorf("%s: %d, want %d", s, ts.Year(), year)
+		}
+	}
+	if def, ai := Lookup("3103001250"); def == nil || ai != "3103" || def.Title != "NET WEIGHT (kg)" {
+		t.Errorf("lookup = %v %q", def, ai)
+	}
+}
@@ -0,0 +1,26 @@
+package zbar
+
+import (
+	"testing"
+
+	"github.com/zooyer/zbar/gs1"
+)
+
+func TestSymbolGS1(t *testing.T) {
+	var data = "0109501101530003\x1d10AB-123"
+	var marked = Symbol{Type: ZBAR_CODE128, Data: data, Modifiers: 1 << uint(ZBAR_MOD_GS1)}
+	if m, err := marked.GS1(); err != nil || m.Batch() != "AB-123" {
+		t.Errorf("GS1 modifier: %+v %v", m, err)
+	}
+
+	var plain = Symbol{Type: ZBAR_CODE128, Data: data}
+	_, err := plain.GS1()
+	if haveModifiers && err != gs1.ErrNotGS1 || !haveModifiers && err != nil {
+		t.Errorf("without modifier: %v", err)
+	}
+
+	var link = Symbol{Type: ZBAR_QRCODE, Data: "https://id.gs1.org/01/09501101530003/10/AB-123"}
+	if m, err := link.GS1(); err != nil || m.GTIN() != "09501101530003" {
+		t.Errorf("digital link: %+v %v", m, err)
+	}
+}
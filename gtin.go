@@ -0,0 +1,43 @@
+package zbar
+
+import (
+	"fmt"
+
+	"github.com/zooyer/zbar/gtin"
+)
+
+/*------------------------------------------------------------*/
+/** @name GTIN normalization
+ * EAN/UPC family results as GTIN-14 catalog keys, see package gtin
+ */
+/*@{*/
+
+/** code family member of each EAN/UPC symbol type. */
+var gtinKinds = map[ZBarSymbolType]gtin.Kind{
+	ZBAR_EAN8:   gtin.EAN8,
+	ZBAR_UPCE:   gtin.UPCE,
+	ZBAR_UPCA:   gtin.UPCA,
+	ZBAR_EAN13:  gtin.EAN13,
+	ZBAR_ISBN10: gtin.ISBN10,
+	ZBAR_ISBN13: gtin.ISBN13,
+}
+
+/** code family member of the main symbol of composites, by length. */
+var compositeKinds = map[int]gtin.Kind{8: gtin.EAN8, 12: gtin.UPCA, 13: gtin.EAN13}
+
+/** normalize an EAN/UPC family symbol to GTIN-14.
+ * any add-on is ignored, see Symbol.Main.
+ * @returns an error for other symbologies and invalid data
+ */
+func (sym Symbol) GTIN() (string, error) {
+	kind, ok := gtinKinds[sym.Type&ZBAR_SYMBOL]
+	if sym.Type == ZBAR_COMPOSITE {
+		kind, ok = compositeKinds[len(sym.mainData())]
+	}
+	if !ok {
+		return "", fmt.Errorf("zbar: %v is not an EAN/UPC symbol", sym.Type)
+	}
+	return gtin.Normalize(kind, sym.mainData())
+}
+
+/*@}*/
@@ -0,0 +1,68 @@
+package gtin
+
+import (
+	"fmt"
+	"strconv"
+)
+
+/*------------------------------------------------------------*/
+/** @name EAN/UPC add-ons
+ * 2 digit add-ons carry the issue number of periodicals, 5 digit
+ * add-ons on books the suggested retail price
+ */
+/*@{*/
+
+/** suggested retail price from a 5 digit book add-on. */
+type Price struct {
+	Currency string  // ISO 4217 code, "" if the add-on carries no price
+	Amount   float64 // price in Currency
+	Note     string  // meaning of special codes, eg "no suggested price"
+}
+
+/** currencies by the first digit of a price add-on. */
+var priceCurrencies = map[byte]string{
+	'0': "GBP", '1': "GBP", '3': "AUD", '4': "NZD", '5': "USD", '6': "CAD",
+}
+
+/** special 5 digit add-ons. */
+var priceNotes = map[string]string{
+	"90000": "no suggested price",
+	"99990": "used book",
+	"99991": "complimentary copy",
+	"59999": "price exceeds USD 99.98",
+}
+
+/** decode a 5 digit book price add-on, eg "52495" is USD 24.95. */
+func ParsePrice(addon string) (Price, error) {
+	if len(addon) != 5 || !digits(addon) {
+		return Price{}, &Error{addon, "not a 5 digit add-on"}
+	}
+	if note, ok := priceNotes[addon]; ok {
+		return Price{Note: note}, nil
+	}
+
+	var currency, ok = priceCurrencies[addon[0]]
+	if !ok {
+		return Price{Note: "internal use"}, nil
+	}
+	cents, _ := strconv.Atoi(addon[1:])
+	return Price{Currency: currency, Amount: float64(cents) / 100}, nil
+}
+
+/** decode a 2 digit periodical add-on: the issue number. */
+func ParseIssue(addon string) (int, error) {
+	if len(addon) != 2 || !digits(addon) {
+		return 0, &Error{addon, "not a 2 digit add-on"}
+	}
+	return strconv.Atoi(addon)
+}
+
+/** price as text, eg "USD 24.95". */
+func (p Price) String() string {
+	if p.Currency == "" {
+		return p.Note
+	}
+	return fmt.Sprintf("%s %.2f", p.Currency, p.Amount)
+}
+
+/*@}*/
@@ -0,0 +1,29 @@
+package gtin
+
+import "testing"
+
+func TestParsePrice(t *testing.T) {
+	for addon, want := range map[string]string{
+		"52495": "USD 24.95",
+		"00799": "GBP 7.99",
+		"61000": "CAD 10.00",
+		"90000": "no suggested price",
+		"99991": "complimentary copy",
+		"81234": "internal use",
+	} {
+		p, err := ParsePrice(addon)
+		if err != nil || p.String() != want {
+			t.Errorf("%s: %v %v, want %s", addon, p, err, want)
+		}
+	}
+	if _, err := ParsePrice("5249"); err == nil {
+		t.Error("4 digits accepted")
+	}
+
+	if n, err := ParseIssue("07"); err != nil || n != 7 {
+		t.Errorf("issue = %d %v", n, err)
+	}
+	if _, err := ParseIssue("7a"); err == nil {
+		t.Error("invalid issue accepted")
+	}
+}
@@ -0,0 +1,144 @@
+/** Package gtin normalizes EAN/UPC family codes to GTIN-14.
+ *
+ * the image scanner reports the family as EAN-8, UPC-E, UPC-A,
+ * EAN-13, ISBN-10 and ISBN-13 results, with or without their check
+ * digit depending on ZBAR_CFG_EMIT_CHECK.  Normalize() turns any of
+ * them into the 14 digit GTIN used as catalog key, verifying the
+ * check digit where it was transmitted.
+ */
+package gtin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zooyer/zbar/gs1"
+	"github.com/zooyer/zbar/isbn"
+)
+
+/** code family member. */
+type Kind int
+
+const (
+	EAN8   Kind = iota /**< 8 digits */
+	UPCE               /**< zero suppressed UPC-A, number system + 6 digits + check */
+	UPCA               /**< 12 digits */
+	EAN13              /**< 13 digits */
+	ISBN10             /**< 10 characters, mod 11 check digit (0-9 or X) */
+	ISBN13             /**< EAN-13 with prefix 978 or 979 */
+	GTIN14             /**< 14 digits, eg from ITF-14 or GS1 AI (01) */
+)
+
+/** length of each kind, including the check digit. */
+var lengths = [...]int{EAN8: 8, UPCE: 8, UPCA: 12, EAN13: 13, ISBN10: 10, ISBN13: 13, GTIN14: 14}
+
+/** invalid code. */
+type Error struct {
+	Code string
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("gtin: %q: %s", e.Code, e.Msg)
+}
+
+/** compute the mod 10 check digit for digits. */
+func CheckDigit(digits string) byte {
+	return gs1.ComputeCheckDigit(digits)
+}
+
+/** verify the mod 10 check digit ending code. */
+func Valid(code string) bool {
+	return gs1.CheckDigit(code)
+}
+
+/** normalize a code to GTIN-14.
+ * code may omit the check digit, which is then computed; a
+ * transmitted check digit is verified.  UPC-E codes are expanded to
+ * UPC-A first (see ExpandUPCE), ISBN-10 codes are converted to their
+ * 978 EAN-13.
+ */
+func Normalize(kind Kind, code string) (string, error) {
+	if kind < EAN8 || kind > GTIN14 {
+		return "", &Error{code, "unknown kind"}
+	}
+
+	var full = lengths[kind]
+	switch kind {
+	case UPCE:
+		upca, err := ExpandUPCE(code)
+		if err != nil {
+			return "", err
+		}
+		return "00" + upca, nil
+	case ISBN10:
+		var isbnCode = strings.ToUpper(code)
+		if len(isbnCode) == full && !isbn.Valid10(isbnCode) {
+			return "", &Error{code, "invalid check digit"}
+		}
+		if len(isbnCode) != full && len(isbnCode) != full-1 || !digits(isbnCode[:9]) {
+			return "", &Error{code, "invalid length or characters"}
+		}
+		code = "978" + isbnCode[:9]
+		full = 13
+	}
+
+	if len(code) != full && len(code) != full-1 || !digits(code) {
+		return "", &Error{code, "invalid length or characters"}
+	}
+	if len(code) == full-1 {
+		code += string(CheckDigit(code))
+	} else if !Valid(code) {
+		return "", &Error{code, "invalid check digit"}
+	}
+	return strings.Repeat("0", 14-len(code)) + code, nil
+}
+
+/** expand a UPC-E code to UPC-A.
+ * accepts the 6 digit body (number system 0), number system and
+ * body, or the full 8 digits including the check digit, which is
+ * verified against the expansion.
+ * @returns the 12 digit UPC-A code
+ */
+func ExpandUPCE(code string) (string, error) {
+	if !digits(code) || len(code) < 6 || len(code) > 8 {
+		return "", &Error{code, "invalid length or characters"}
+	}
+
+	var ns, body, check = "0", code, ""
+	if len(code) >= 7 {
+		ns, body = code[:1], code[1:7]
+		check = code[7:]
+	}
+	if ns != "0" && ns != "1" {
+		return "", &Error{code, "invalid number system"}
+	}
+
+	var manufacturer, product string
+	switch last := body[5]; last {
+	case '0', '1', '2':
+		manufacturer, product = body[:2]+string(last)+"00", "00"+body[2:5]
+	case '3':
+		manufacturer, product = body[:3]+"00", "000"+body[3:5]
+	case '4':
+		manufacturer, product = body[:4]+"0", "0000"+body[4:5]
+	default:
+		manufacturer, product = body[:5], "0000"+string(last)
+	}
+
+	var upca = ns + manufacturer + product
+	upca += string(CheckDigit(upca))
+	if check != "" && check != upca[11:] {
+		return "", &Error{code, "invalid check digit"}
+	}
+	return upca, nil
+}
+
+func digits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return len(s) > 0
+}
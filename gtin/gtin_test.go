@@ -0,0 +1,73 @@
+package gtin
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	var cases = []struct {
+		kind Kind
+		code string
+		want string
+	}{
+		{EAN13, "4006381333931", "04006381333931"},
+		{EAN13, "400638133393", "04006381333931"},
+		{EAN8, "96385074", "00000096385074"},
+		{UPCA, "036000291452", "00036000291452"},
+		{UPCE, "04252614", "00042100005264"},
+		{UPCE, "0425261", "00042100005264"},
+		{UPCE, "425261", "00042100005264"},
+		{ISBN10, "0306406152", "09780306406157"},
+		{ISBN10, "030640615", "09780306406157"},
+		{ISBN13, "9780306406157", "09780306406157"},
+		{GTIN14, "10614141000415", "10614141000415"},
+	}
+	for _, c := range cases {
+		got, err := Normalize(c.kind, c.code)
+		if err != nil || got != c.want {
+			t.Errorf("%d %s: %s %v, want %s", c.kind, c.code, got, err, c.want)
+		}
+	}
+
+	for _, c := range []struct {
+		kind Kind
+		code string
+	}{
+		{EAN13, "4006381333932"}, {EAN13, "40063813339"}, {EAN8, "9638507a"},
+		{UPCE, "04252615"}, {UPCE, "24252614"}, {ISBN10, "0306406153"},
+	} {
+		if _, err := Normalize(c.kind, c.code); err == nil {
+			t.Errorf("%d %s: expected error", c.kind, c.code)
+		}
+	}
+}
+
+func TestExpandUPCE(t *testing.T) {
+	for upce, upca := range map[string]string{
+		"0123450": "012000003455", // manufacturer 12000, product 00345
+		"0123453": "012300000451",
+		"0123454": "012340000053",
+		"0123455": "012345000058",
+		"1987657": "198765000073",
+	} {
+		if got, err := ExpandUPCE(upce); err != nil || got != upca {
+			t.Errorf("%s: %s %v, want %s", upce, got, err, upca)
+		}
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	for gtin, name := range map[string]string{
+		"04006381333931": "GS1 Germany",
+		"00036000291452": "GS1 US",
+		"09780306406157": "Bookland (ISBN)",
+		"00000096385074": "GS1 Global Office (GTIN-8)",
+		"00000020123457": "Restricted distribution",
+		"17612345678903": "GS1 Switzerland",
+	} {
+		if r, ok := Prefix(gtin); !ok || r.Name != name {
+			t.Errorf("%s: %v %v, want %s", gtin, r, ok, name)
+		}
+	}
+	if _, ok := Prefix("01400000000000"); ok {
+		t.Error("unassigned prefix 140")
+	}
+}
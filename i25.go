@@ -0,0 +1,122 @@
+package zbar
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/zooyer/zbar/gtin"
+)
+
+/*------------------------------------------------------------*/
+/** @name Interleaved 2 of 5 policy
+ * Interleaved 2 of 5 has no built-in check and partial scans of a
+ * symbol are valid symbols themselves.  a profile's I25 policy only
+ * accepts reads of permitted lengths and, for ITF-14 shipping
+ * container codes, with a valid check digit; rejected reads are
+ * counted
+ */
+/*@{*/
+
+/** I25 acceptance policy. */
+type I25Policy struct {
+	Lengths []int `json:"lengths,omitempty"` // permitted lengths, eg [10, 14]; empty for any
+	ITF14   bool  `json:"itf14,omitempty"`   // 14 digit reads must have a valid GTIN check digit
+}
+
+/** counts of reads rejected by a Scanner's policies. */
+type Rejects struct {
+	I25Length int // I25 reads of a length not permitted
+	I25Check  int // ITF-14 reads with an invalid check digit
+}
+
+/** check the policy and narrow the library's length limits to the
+ * permitted lengths.
+ */
+func (p *I25Policy) configure(scanner *ZBarImageScanner) error {
+	if len(p.Lengths) == 0 {
+		return nil
+	}
+
+	var lengths = append([]int(nil), p.Lengths...)
+	sort.Ints(lengths)
+	if lengths[0] < 1 {
+		return fmt.Errorf("zbar: invalid I25 length %d", lengths[0])
+	}
+	ZBarImageScannerSetConfig(scanner, ZBAR_I25, ZBAR_CFG_MIN_LEN, lengths[0])
+	ZBarImageScannerSetConfig(scanner, ZBAR_I25, ZBAR_CFG_MAX_LEN, lengths[len(lengths)-1])
+	return nil
+}
+
+/** check a read against the policy.
+ * @returns false if the read is rejected, counting it in r unless r
+ * is nil
+ */
+func (p *I25Policy) accept(sym Symbol, r *Rejects) bool {
+	if sym.Type&ZBAR_SYMBOL != ZBAR_I25 {
+		return true
+	}
+
+	if len(p.Lengths) > 0 {
+		var ok bool
+		for _, n := range p.Lengths {
+			ok = ok || len(sym.Data) == n
+		}
+		if !ok {
+			if r != nil {
+				r.I25Length++
+			}
+			return false
+		}
+	}
+	if p.ITF14 && len(sym.Data) == 14 && !gtin.Valid(sym.Data) {
+		if r != nil {
+			r.I25Check++
+		}
+		return false
+	}
+	return true
+}
+
+/** remove the reads rejected by the profile's policies.
+ * applied once per scanned image, after the results of all passes
+ * are merged, so every rejected read is counted once.
+ */
+func (s *Scanner) filter(symbols []Symbol) []Symbol {
+	if s.profile.I25 == nil {
+		return symbols
+	}
+
+	var kept = symbols[:0]
+	for _, sym := range symbols {
+		if s.profile.I25.accept(sym, &s.rejects) {
+			kept = append(kept, sym)
+		}
+	}
+	return kept
+}
+
+/** check whether any read passes the profile's policies, without
+ * counting rejects.  used to decide on retry passes.
+ */
+func (s *Scanner) accepts(symbols []Symbol) bool {
+	for _, sym := range symbols {
+		if s.profile.I25 == nil || s.profile.I25.accept(sym, nil) {
+			return true
+		}
+	}
+	return false
+}
+
+/** counts of reads rejected since the scanner was created or the
+ * counts were last reset.
+ */
+func (s *Scanner) Rejects() Rejects {
+	return s.rejects
+}
+
+/** reset the counts of rejected reads. */
+func (s *Scanner) ResetRejects() {
+	s.rejects = Rejects{}
+}
+
+/*@}*/
@@ -0,0 +1,79 @@
+package zbar
+
+import (
+	"image"
+	"testing"
+)
+
+func TestI25Policy(t *testing.T) {
+	var s = &Scanner{profile: Profile{I25: &I25Policy{Lengths: []int{10, 14}, ITF14: true}}}
+
+	var symbols = s.filter([]Symbol{
+		{Type: ZBAR_I25, Data: "1234567890"},
+		{Type: ZBAR_I25, Data: "12345678"},
+		{Type: ZBAR_I25, Data: "10614141000415"},
+		{Type: ZBAR_I25, Data: "10614141000416"},
+		{Type: ZBAR_CODE128, Data: "12345678"},
+	})
+	if len(symbols) != 3 || symbols[0].Data != "1234567890" || symbols[1].Data != "10614141000415" || symbols[2].Type != ZBAR_CODE128 {
+		t.Errorf("kept %+v", symbols)
+	}
+	if r := s.Rejects(); r != (Rejects{I25Length: 1, I25Check: 1}) {
+		t.Errorf("rejects = %+v", r)
+	}
+	s.ResetRejects()
+	if r := s.Rejects(); r != (Rejects{}) {
+		t.Errorf("rejects after reset = %+v", r)
+	}
+}
+
+func TestI25RejectsOncePerScan(t *testing.T) {
+	// every pass reads a valid ITF-14 and a partial read at the same place
+	var passes int
+	var decode = func(gray *image.Gray, sequence uint32) ([]Symbol, error) {
+		passes++
+		var at = gray.Bounds().Min
+		var loc = []image.Point{at.Add(image.Pt(2, 2)), at.Add(image.Pt(20, 2))}
+		return []Symbol{
+			{Type: ZBAR_I25, Data: "10614141000415", Points: loc},
+			{Type: ZBAR_I25, Data: "061414", Points: loc},
+		}, nil
+	}
+	var img = image.NewGray(image.Rect(0, 0, 32, 32))
+
+	for name, profile := range map[string]Profile{
+		"tiles":   {TileSize: 16, TileOverlap: 12},
+		"pyramid": {Scales: []float64{0.5, 2}},
+		"invert":  {Invert: InvertBoth},
+	} {
+		profile.I25 = &I25Policy{Lengths: []int{14}, ITF14: true}
+		passes = 0
+		var s = &Scanner{profile: profile, decode: decode}
+		symbols, err := s.Scan(img)
+		if err != nil || len(symbols) != 1 || symbols[0].Data != "10614141000415" {
+			t.Errorf("%s: %+v %v", name, symbols, err)
+		}
+		if passes < 2 {
+			t.Errorf("%s: %d passes", name, passes)
+		}
+		if r := s.Rejects(); r != (Rejects{I25Length: 1}) {
+			t.Errorf("%s: rejects = %+v after %d passes", name, r, passes)
+		}
+	}
+
+	// only rejected reads: every angle is retried, the read is counted once
+	passes = 0
+	var s = &Scanner{
+		profile: Profile{Rotate: []float64{15, 30, 45}, I25: &I25Policy{Lengths: []int{10}}},
+		decode: func(gray *image.Gray, sequence uint32) ([]Symbol, error) {
+			passes++
+			return []Symbol{{Type: ZBAR_I25, Data: "061414", Points: []image.Point{gray.Bounds().Min}}}, nil
+		},
+	}
+	if symbols, err := s.Scan(img); err != nil || len(symbols) != 0 || passes != 4 {
+		t.Errorf("rotate: %+v %v after %d passes", symbols, err, passes)
+	}
+	if r := s.Rejects(); r != (Rejects{I25Length: 1}) {
+		t.Errorf("rotate: rejects = %+v", r)
+	}
+}
@@ -0,0 +1,68 @@
+package zbar
+
+import (
+	"fmt"
+	"image"
+)
+
+/*------------------------------------------------------------*/
+/** @name Inverted symbol scanning
+ * the decoders expect dark bars on a light background, so light on
+ * dark symbols (laser etched parts, phone screens) are not found.
+ * when enabled by the profile the inverted image is scanned as well;
+ * symbols found that way are marked Symbol.Inverted
+ */
+/*@{*/
+
+/** Profile.Invert modes. */
+const (
+	InvertRetry = "retry" /**< scan inverted only if nothing was found */
+	InvertBoth  = "both"  /**< always scan both polarities */
+)
+
+func validInvert(mode string) error {
+	switch mode {
+	case "", InvertRetry, InvertBoth:
+		return nil
+	}
+	return fmt.Errorf("zbar: invalid invert mode %q", mode)
+}
+
+/** scan with the polarities selected by the profile. */
+func (s *Scanner) scanPolarity(gray *image.Gray, sequence uint32) ([]Symbol, error) {
+	symbols, err := s.pass(gray, sequence)
+	if err != nil {
+		return nil, err
+	}
+
+	switch s.profile.Invert {
+	case InvertRetry:
+		if s.accepts(symbols) {
+			return symbols, nil
+		}
+	case InvertBoth:
+	default:
+		return symbols, nil
+	}
+
+	found, err := s.pass(invertGray(gray), sequence)
+	if err != nil {
+		return nil, err
+	}
+	for _, sym := range found {
+		sym.Inverted = true
+		symbols = mergeSymbol(symbols, sym)
+	}
+	return symbols, nil
+}
+
+/** luminance negative of an image. */
+func invertGray(src *image.Gray) *image.Gray {
+	var lut [256]uint8
+	for i := range lut {
+		lut[i] = uint8(255 - i)
+	}
+	return applyLUT(src, &lut)
+}
+
+/*@}*/
@@ -0,0 +1,31 @@
+package zbar
+
+import (
+	"image"
+	"testing"
+)
+
+func TestInvertGray(t *testing.T) {
+	var src = image.NewGray(image.Rect(5, 5, 7, 6))
+	copy(src.Pix, []uint8{0, 200})
+
+	var inv = invertGray(src)
+	if inv.Bounds() != src.Bounds() {
+		t.Fatalf("bounds = %v", inv.Bounds())
+	}
+	if inv.Pix[0] != 255 || inv.Pix[1] != 55 {
+		t.Errorf("pix = %v", inv.Pix)
+	}
+	if src.Pix[0] != 0 {
+		t.Error("source image modified")
+	}
+
+	for _, mode := range []string{"", InvertRetry, InvertBoth} {
+		if err := validInvert(mode); err != nil {
+			t.Error(err)
+		}
+	}
+	if validInvert("always") == nil {
+		t.Error("invalid mode accepted")
+	}
+}
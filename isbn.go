@@ -0,0 +1,25 @@
+package zbar
+
+import "github.com/zooyer/zbar/isbn"
+
+/*------------------------------------------------------------*/
+/** @name ISBN results
+ * ISBN-10, ISBN-13 and EAN-13 symbols in the 978/979 Bookland
+ * ranges are parsed into Symbol.ISBN, see package isbn
+ */
+/*@{*/
+
+/** ISBN of a symbol, nil for other symbols. */
+func symbolISBN(sym Symbol) *isbn.ISBN {
+	g, err := sym.GTIN()
+	if err != nil || (g[1:4] != "978" && g[1:4] != "979") {
+		return nil
+	}
+	i, err := isbn.Parse(g[1:])
+	if err != nil {
+		return nil
+	}
+	return i
+}
+
+/*@}*/
@@ -0,0 +1,188 @@
+/** Package isbn converts, validates and hyphenates ISBNs.
+ *
+ * hyphenation follows the ranges of the International ISBN Agency
+ * range message embedded as RangeMessage.xml (see ranges.go); ISBNs
+ * in ranges it does not list are valid but cannot be hyphenated.
+ */
+package isbn
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	/** the ISBN has an invalid length, characters or check digit. */
+	ErrInvalid = errors.New("isbn: invalid ISBN")
+	/** the ISBN-13 has no ISBN-10 equivalent (979 prefix). */
+	ErrNo10 = errors.New("isbn: 979 ISBNs have no ISBN-10 form")
+	/** the ISBN lies in a range missing from the embedded range message. */
+	ErrUnknownRange = errors.New("isbn: ISBN range unknown")
+)
+
+/** parsed ISBN. */
+type ISBN struct {
+	ISBN13 string // 13 digits
+	ISBN10 string // 10 characters, "" for 979 ISBNs
+
+	// elements, empty if the range is unknown
+	Prefix      string // "978" or "979"
+	Group       string // registration group, eg "0" for English
+	Registrant  string
+	Publication string
+}
+
+/** parse an ISBN-10 or ISBN-13, with or without hyphens or spaces.
+ * the check digit is verified.
+ */
+func Parse(s string) (*ISBN, error) {
+	var isbn13 string
+	switch s = Clean(s); len(s) {
+	case 10:
+		if !Valid10(s) {
+			return nil, ErrInvalid
+		}
+		isbn13, _ = To13(s)
+	case 13:
+		if !Valid13(s) {
+			return nil, ErrInvalid
+		}
+		isbn13 = s
+	default:
+		return nil, ErrInvalid
+	}
+
+	var isbn = &ISBN{ISBN13: isbn13}
+	isbn.ISBN10, _ = To10(isbn13)
+	isbn.Prefix, isbn.Group, isbn.Registrant, isbn.Publication, _ = split(isbn13)
+	return isbn, nil
+}
+
+/** hyphenated ISBN-13, eg "978-0-306-40615-7", or the plain ISBN-13
+ * if the range is unknown.
+ */
+func (i *ISBN) Hyphenated13() string {
+	if i.Group == "" {
+		return i.ISBN13
+	}
+	return strings.Join([]string{i.Prefix, i.Group, i.Registrant, i.Publication, i.ISBN13[12:]}, "-")
+}
+
+/** hyphenated ISBN-10, eg "0-306-40615-2", or the plain ISBN-10 if
+ * the range is unknown, "" for 979 ISBNs.
+ */
+func (i *ISBN) Hyphenated10() string {
+	if i.ISBN10 == "" || i.Group == "" {
+		return i.ISBN10
+	}
+	return strings.Join([]string{i.Group, i.Registrant, i.Publication, i.ISBN10[9:]}, "-")
+}
+
+func (i *ISBN) String() string {
+	return i.Hyphenated13()
+}
+
+/** hyphenate an ISBN-10 or ISBN-13. */
+func Hyphenate(s string) (string, error) {
+	isbn, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	if isbn.Group == "" {
+		return "", ErrUnknownRange
+	}
+	if len(Clean(s)) == 10 {
+		return isbn.Hyphenated10(), nil
+	}
+	return isbn.Hyphenated13(), nil
+}
+
+/** split an ISBN-13 into prefix, group, registrant and publication. */
+func split(isbn13 string) (prefix, group, registrant, publication string, err error) {
+	prefix = isbn13[:3]
+	var rest = isbn13[3:12]
+
+	var n = lookup(prefix, rest)
+	if n == 0 {
+		return "", "", "", "", ErrUnknownRange
+	}
+	group, rest = rest[:n], rest[n:]
+
+	if n = lookup(prefix+"-"+group, rest); n == 0 || n >= len(rest) {
+		return "", "", "", "", ErrUnknownRange
+	}
+	return prefix, group, rest[:n], rest[n:], nil
+}
+
+/** remove hyphens and spaces, upper case a trailing x. */
+func Clean(s string) string {
+	s = strings.NewReplacer("-", "", " ", "").Replace(s)
+	return strings.ToUpper(s)
+}
+
+/** convert an ISBN-10 to ISBN-13. */
+func To13(isbn10 string) (string, error) {
+	if isbn10 = Clean(isbn10); !Valid10(isbn10) {
+		return "", ErrInvalid
+	}
+	var isbn13 = "978" + isbn10[:9]
+	return isbn13 + string(check13(isbn13)), nil
+}
+
+/** convert a 978 ISBN-13 to ISBN-10. */
+func To10(isbn13 string) (string, error) {
+	if isbn13 = Clean(isbn13); !Valid13(isbn13) {
+		return "", ErrInvalid
+	}
+	if isbn13[:3] != "978" {
+		return "", ErrNo10
+	}
+	return isbn13[3:12] + string(check10(isbn13[3:12])), nil
+}
+
+/** verify an ISBN-10 (mod 11 check digit, 0-9 or X). */
+func Valid10(isbn10 string) bool {
+	return len(isbn10) == 10 && digits(isbn10[:9]) && check10(isbn10[:9]) == isbn10[9]
+}
+
+/** verify an ISBN-13 (978 or 979 prefix, mod 10 check digit). */
+func Valid13(isbn13 string) bool {
+	return len(isbn13) == 13 && digits(isbn13) &&
+		(isbn13[:3] == "978" || isbn13[:3] == "979") && check13(isbn13[:12]) == isbn13[12]
+}
+
+/** ISBN-10 check digit: weights 10 down to 2, mod 11, X for 10. */
+func check10(digits string) byte {
+	var sum int
+	for i := 0; i < 9; i++ {
+		sum += (10 - i) * int(digits[i]-'0')
+	}
+	switch c := (11 - sum%11) % 11; c {
+	case 10:
+		return 'X'
+	default:
+		return byte('0' + c)
+	}
+}
+
+/** ISBN-13 check digit: EAN-13 mod 10. */
+func check13(digits string) byte {
+	var sum int
+	for i := 0; i < 12; i++ {
+		var d = int(digits[i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func digits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,84 @@
+package isbn
+
+import "testing"
+
+func TestConvert(t *testing.T) {
+	if s, err := To13("0-306-40615-2"); err != nil || s != "9780306406157" {
+		t.Errorf("To13 = %s %v", s, err)
+	}
+	if s, err := To10("978-0-306-40615-7"); err != nil || s != "0306406152" {
+		t.Errorf("To10 = %s %v", s, err)
+	}
+	if s, err := To10("9781843560333"); err != nil || s != "184356033X" {
+		t.Errorf("To10 X = %s %v", s, err)
+	}
+	if _, err := To10("9791090636071"); err != ErrNo10 {
+		t.Errorf("To10 979 = %v", err)
+	}
+	for _, bad := range []string{"0306406153", "9780306406158", "4006381333931", "030640615"} {
+		if _, err := Parse(bad); err != ErrInvalid {
+			t.Errorf("%s: %v", bad, err)
+		}
+	}
+}
+
+func TestHyphenate(t *testing.T) {
+	var cases = map[string]string{
+		"9780306406157": "978-0-306-40615-7",
+		"0306406152":    "0-306-40615-2",
+		"184356033x":    "1-84356-033-X",
+		"9783161484100": "978-3-16-148410-0",
+		"9787111075752": "978-7-111-07575-2",
+		"9791090636071": "979-10-90636-07-1",
+		"9782070368228": "978-2-07-036822-8",
+		"9788437604947": "978-84-376-0494-7",
+		"9788954600125": "978-89-546-0012-5",
+		"9786061234561": "978-606-12-3456-1",
+		"9798655183377": "979-8-6551-8337-7",
+		"9785000000007": "978-5-00000-000-7",
+	}
+	for in, want := range cases {
+		if got, err := Hyphenate(in); err != nil || got != want {
+			t.Errorf("%s: %s %v, want %s", in, got, err, want)
+		}
+	}
+
+	// valid, but group 99937 is missing from the embedded table
+	isbn, err := Parse("9789993700005")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isbn.Group != "" || isbn.Hyphenated13() != "9789993700005" {
+		t.Errorf("unknown range: %+v", isbn)
+	}
+	if _, err := Hyphenate("9789993700005"); err != ErrUnknownRange {
+		t.Errorf("unknown range: %v", err)
+	}
+}
+
+func TestRangeMessage(t *testing.T) {
+	rulesOnce.Do(loadRules)
+	for key, list := range rules {
+		if len(list) == 0 {
+			t.Errorf("%s: no rules", key)
+		}
+		for i, r := range list {
+			if r.lo > r.hi || r.hi > 9999999 || r.length > 7 || i > 0 && r.lo <= list[i-1].hi {
+				t.Errorf("%s: invalid or overlapping rule %+v", key, r)
+			}
+		}
+	}
+	for _, prefix := range []string{"978", "979"} {
+		var list = rules[prefix]
+		for i, r := range list {
+			if i == 0 && r.lo != 0 || i > 0 && r.lo != list[i-1].hi+1 || i == len(list)-1 && r.hi != 9999999 {
+				t.Errorf("%s: rules do not cover all groups at %+v", prefix, r)
+			}
+		}
+	}
+	for _, key := range []string{"978", "979", "978-0", "978-2", "978-84", "978-89", "978-606", "979-8"} {
+		if len(rules[key]) == 0 {
+			t.Errorf("%s missing", key)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package isbn
+
+import (
+	_ "embed"
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:generate curl -fsSL -o RangeMessage.xml https://www.isbn-international.org/export_rangemessage.xml
+
+/*------------------------------------------------------------*/
+/** @name ISBN ranges
+ * the International ISBN Agency range message (RangeMessage.xml),
+ * embedded and parsed on first use.  the file is updated unchanged
+ * from the agency's export with go generate.  each rule maps the next 7
+ * digits, read as a number, to the length of the following element:
+ * the registration group for "978" and "979", the registrant for
+ * "978-0" etc.  a length of 0 marks ranges that are not in use
+ */
+/*@{*/
+
+//go:embed RangeMessage.xml
+var rangeMessage []byte
+
+/** range rule. */
+type rule struct {
+	lo, hi int
+	length int
+}
+
+/** RangeMessage.xml structure. */
+type xmlRanges struct {
+	Prefixes []xmlGroup `xml:"EAN.UCCPrefixes>EAN.UCC"`
+	Groups   []xmlGroup `xml:"RegistrationGroups>Group"`
+}
+
+type xmlGroup struct {
+	Prefix string `xml:"Prefix"`
+	Rules  []struct {
+		Range  string `xml:"Range"`
+		Length int    `xml:"Length"`
+	} `xml:"Rules>Rule"`
+}
+
+var (
+	rulesOnce sync.Once
+	/** rules by EAN prefix (registration groups) and by EAN prefix and
+	 * registration group (registrants).
+	 */
+	rules map[string][]rule
+)
+
+/** parse the embedded range message.
+ * it is part of the build, so a malformed message is a programming
+ * error.
+ */
+func loadRules() {
+	var msg xmlRanges
+	if err := xml.Unmarshal(rangeMessage, &msg); err != nil {
+		panic("isbn: RangeMessage.xml: " + err.Error())
+	}
+
+	rules = make(map[string][]rule)
+	for _, g := range append(msg.Prefixes, msg.Groups...) {
+		for _, r := range g.Rules {
+			lo, hi, ok := strings.Cut(r.Range, "-")
+			var l, err1 = strconv.Atoi(lo)
+			var h, err2 = strconv.Atoi(hi)
+			if !ok || err1 != nil || err2 != nil {
+				panic("isbn: RangeMessage.xml: invalid range " + r.Range + " in " + g.Prefix)
+			}
+			rules[g.Prefix] = append(rules[g.Prefix], rule{l, h, r.Length})
+		}
+	}
+}
+
+/** look up the element length for the 7 digit window of digits. */
+func lookup(key, digits string) int {
+	rulesOnce.Do(loadRules)
+
+	var window = digits
+	for len(window) < 7 {
+		window += "0"
+	}
+	var n int
+	for _, c := range window[:7] {
+		n = n*10 + int(c-'0')
+	}
+	for _, r := range rules[key] {
+		if n >= r.lo && n <= r.hi {
+			return r.length
+		}
+	}
+	return 0
+}
+
+/*@}*/
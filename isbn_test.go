@@ -0,0 +1,26 @@
+package zbar
+
+import "testing"
+
+func TestSymbolISBN(t *testing.T) {
+	for _, sym := range []Symbol{
+		{Type: ZBAR_ISBN10, Data: "0306406152"},
+		{Type: ZBAR_ISBN13, Data: "9780306406157"},
+		{Type: ZBAR_EAN13, Data: "978030640615"},
+		{Type: ZBAR_EAN13 | ZBAR_ADDON5, Data: "978030640615752495", Main: "9780306406157", AddOn: "52495", AddOnType: ZBAR_ADDON5},
+	} {
+		var i = symbolISBN(sym)
+		if i == nil || i.Hyphenated13() != "978-0-306-40615-7" || i.Hyphenated10() != "0-306-40615-2" {
+			t.Errorf("%v %s: %+v", sym.Type, sym.Data, i)
+		}
+	}
+
+	for _, sym := range []Symbol{
+		{Type: ZBAR_EAN13, Data: "4006381333931"},
+		{Type: ZBAR_QRCODE, Data: "9780306406157"},
+	} {
+		if i := symbolISBN(sym); i != nil {
+			t.Errorf("%v %s: %+v", sym.Type, sym.Data, i)
+		}
+	}
+}
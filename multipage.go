@@ -0,0 +1,202 @@
+package zbar
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"io"
+
+	"golang.org/x/image/tiff"
+)
+
+/*------------------------------------------------------------*/
+/** @name Multi-page scanning
+ * scans every page of a multi-page TIFF or every frame of an animated
+ * GIF, tagging each image with its 0-based page index as sequence
+ * number (see zbar_image_set_sequence())
+ */
+/*@{*/
+
+/** returned for TIFF files with a malformed IFD chain. */
+var ErrBadTIFF = errors.New("zbar: malformed tiff")
+
+/** upper bound on the pages of a single document, guards against IFD loops. */
+const maxPages = 10000
+
+/** scan every page/frame of an image document.
+ * multi-page TIFF and animated GIF are decoded page by page, any other
+ * format registered with the image package is scanned as a single page.
+ * each returned Symbol reports the page it was found on in Sequence.
+ * @param stopAtFirst stop after the first page that yields any symbol
+ */
+func (s *Scanner) ScanPages(r io.Reader, stopAtFirst bool) ([]Symbol, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var symbols []Symbol
+	var scanErr error
+	err = decodePages(data, func(page int, img image.Image) bool {
+		found, err := s.scanImage(img, uint32(page))
+		if err != nil {
+			scanErr = err
+			return false
+		}
+		symbols = append(symbols, found...)
+		return !(stopAtFirst && len(found) > 0)
+	})
+	if err == nil {
+		err = scanErr
+	}
+
+	return symbols, err
+}
+
+/** decode the pages of a document, calling fn for each until it
+ * returns false.  the image passed to fn is only valid during the call.
+ */
+func decodePages(data []byte, fn func(page int, img image.Image) bool) error {
+	switch {
+	case bytes.HasPrefix(data, []byte("GIF8")):
+		g, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		gifFrames(g, fn)
+		return nil
+
+	case bytes.HasPrefix(data, []byte("II*\x00")), bytes.HasPrefix(data, []byte("MM\x00*")):
+		ifds, err := tiffIFDs(data)
+		if err != nil {
+			return err
+		}
+		for page, ifd := range ifds {
+			var p = &tiffPage{data: data, ifd: ifd}
+			img, err := tiff.Decode(io.NewSectionReader(p, 0, int64(len(data))))
+			if err != nil {
+				return err
+			}
+			if !fn(page, img) {
+				break
+			}
+		}
+		return nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	fn(0, img)
+	return nil
+}
+
+/** composite the frames of an animated GIF honouring their disposal
+ * methods.  the canvas starts out white rather than transparent so
+ * uncovered areas read as background instead of black bars.
+ */
+func gifFrames(g *gif.GIF, fn func(page int, img image.Image) bool) {
+	var bounds = image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	if bounds.Empty() && len(g.Image) > 0 {
+		bounds = g.Image[0].Bounds()
+	}
+
+	var canvas = image.NewRGBA(bounds)
+	draw.Draw(canvas, bounds, image.White, image.Point{}, draw.Src)
+
+	for i, frame := range g.Image {
+		var disposal byte
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+
+		var previous *image.RGBA
+		if disposal == gif.DisposalPrevious {
+			previous = image.NewRGBA(bounds)
+			copy(previous.Pix, canvas.Pix)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		if !fn(i, canvas) {
+			return
+		}
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+}
+
+/** walk the IFD chain of a (classic, non-Big) TIFF file.
+ * @returns the offset of every image file directory in file order
+ */
+func tiffIFDs(data []byte) ([]uint32, error) {
+	if len(data) < 8 {
+		return nil, ErrBadTIFF
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if data[0] == 'M' {
+		order = binary.BigEndian
+	}
+
+	var ifds []uint32
+	var seen = make(map[uint32]bool)
+	for off := order.Uint32(data[4:8]); off != 0; {
+		if seen[off] || len(ifds) >= maxPages || uint64(off)+2 > uint64(len(data)) {
+			return nil, ErrBadTIFF
+		}
+		seen[off] = true
+		ifds = append(ifds, off)
+
+		var next = uint64(off) + 2 + 12*uint64(order.Uint16(data[off:]))
+		if next+4 > uint64(len(data)) {
+			return nil, ErrBadTIFF
+		}
+		off = order.Uint32(data[next:])
+	}
+
+	return ifds, nil
+}
+
+/** view of a TIFF file whose header points at the given IFD, so the
+ * single image TIFF decoder reads that page.
+ */
+type tiffPage struct {
+	data []byte
+	ifd  uint32
+}
+
+func (p *tiffPage) ReadAt(b []byte, off int64) (int, error) {
+	if off >= int64(len(p.data)) {
+		return 0, io.EOF
+	}
+
+	var n = copy(b, p.data[off:])
+	var header [4]byte
+	if p.data[0] == 'M' {
+		binary.BigEndian.PutUint32(header[:], p.ifd)
+	} else {
+		binary.LittleEndian.PutUint32(header[:], p.ifd)
+	}
+	for i := int64(0); i < int64(n); i++ {
+		if pos := off + i; pos >= 4 && pos < 8 {
+			b[i] = header[pos-4]
+		}
+	}
+
+	if n < len(b) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+/*@}*/
@@ -0,0 +1,69 @@
+package zbar
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+)
+
+func TestTiffIFDs(t *testing.T) {
+	// header, IFD at 8 (no entries) -> IFD at 14 (no entries) -> end
+	var data = []byte{
+		'I', 'I', 42, 0, 8, 0, 0, 0,
+		0, 0, 14, 0, 0, 0,
+		0, 0, 0, 0, 0, 0,
+	}
+	ifds, err := tiffIFDs(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ifds) != 2 || ifds[0] != 8 || ifds[1] != 14 {
+		t.Fatalf("ifds = %v", ifds)
+	}
+
+	// second IFD points back to the first
+	data[14+2] = 8
+	if _, err = tiffIFDs(data); err != ErrBadTIFF {
+		t.Fatalf("loop: err = %v", err)
+	}
+
+	var page = &tiffPage{data: data, ifd: 14}
+	var header = make([]byte, 8)
+	page.ReadAt(header, 0)
+	if header[4] != 14 || header[0] != 'I' {
+		t.Fatalf("header = %v", header)
+	}
+}
+
+func TestDecodePagesGIF(t *testing.T) {
+	var palette = color.Palette{color.White, color.Black}
+	var g = &gif.GIF{Config: image.Config{ColorModel: palette, Width: 4, Height: 4}}
+	for i := 0; i < 3; i++ {
+		var frame = image.NewPaletted(image.Rect(0, 0, 4, 4), palette)
+		frame.SetColorIndex(i, i, 1)
+		g.Image = append(g.Image, frame)
+		g.Delay = append(g.Delay, 0)
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+
+	var pages []int
+	err := decodePages(buf.Bytes(), func(page int, img image.Image) bool {
+		pages = append(pages, page)
+		if r, _, _, _ := img.At(page, page).RGBA(); r != 0 {
+			t.Errorf("page %d: pixel not drawn", page)
+		}
+		return page < 1
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("pages = %v, want stop after second", pages)
+	}
+}
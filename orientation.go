@@ -0,0 +1,93 @@
+package zbar
+
+import (
+	"image"
+	"math"
+)
+
+/*------------------------------------------------------------*/
+/** @name Symbol orientation
+ * zbar_symbol_get_orientation() is only available with libzbar 0.11
+ * and later; build with the zbar011 tag to use it (see zbar011.go).
+ * otherwise, and whenever the library cannot tell, the orientation of
+ * four point (QR code) locations is estimated from the order of their
+ * corners
+ */
+/*@{*/
+
+/** decoded symbol coarse orientation.
+ * @since 0.11
+ */
+type ZBarOrientation int
+
+const (
+	ZBAR_ORIENT_UNKNOWN ZBarOrientation = -1 + iota /**< unable to determine orientation */
+	ZBAR_ORIENT_UP                                  /**< upright, read left to right */
+	ZBAR_ORIENT_RIGHT                               /**< sideways, read top to bottom */
+	ZBAR_ORIENT_DOWN                                /**< upside-down, read right to left */
+	ZBAR_ORIENT_LEFT                                /**< sideways, read bottom to top */
+)
+
+/** orientation name. */
+func (o ZBarOrientation) String() string {
+	switch o {
+	case ZBAR_ORIENT_UP:
+		return "UP"
+	case ZBAR_ORIENT_RIGHT:
+		return "RIGHT"
+	case ZBAR_ORIENT_DOWN:
+		return "DOWN"
+	case ZBAR_ORIENT_LEFT:
+		return "LEFT"
+	}
+	return "UNKNOWN"
+}
+
+/** clockwise rotation in degrees that turns the symbol upright.
+ * 0 for upright and unknown orientations.
+ */
+func (o ZBarOrientation) Degrees() int {
+	if o < ZBAR_ORIENT_UP {
+		return 0
+	}
+	return (4 - int(o)) % 4 * 90
+}
+
+/** orientation after the image is turned quarters*90 degrees clockwise. */
+func (o ZBarOrientation) turn(quarters int) ZBarOrientation {
+	if o < ZBAR_ORIENT_UP {
+		return o
+	}
+	return ZBarOrientation(((int(o)+quarters)%4 + 4) % 4)
+}
+
+/** orientation of a symbol: reported by the library when possible,
+ * else estimated from its location.
+ */
+func symbolOrientation(sym *ZBarSymbol, points []image.Point) ZBarOrientation {
+	if o := ZBarSymbolGetOrientation(sym); o != ZBAR_ORIENT_UNKNOWN {
+		return o
+	}
+	return estimateOrientation(points)
+}
+
+/** estimate the orientation of a four point location.
+ * the points are the corners top left, bottom left, bottom right, top
+ * right of the upright symbol, so the direction from the first to the
+ * last corner is the reading direction.
+ */
+func estimateOrientation(points []image.Point) ZBarOrientation {
+	if len(points) != 4 {
+		return ZBAR_ORIENT_UNKNOWN
+	}
+
+	var d = points[3].Sub(points[0])
+	if d == (image.Point{}) {
+		return ZBAR_ORIENT_UNKNOWN
+	}
+	// angle clockwise from the x axis, in quarter turns
+	var q = int(math.Round(math.Atan2(float64(d.Y), float64(d.X)) / (math.Pi / 2)))
+	return ZBAR_ORIENT_UP.turn(q)
+}
+
+/*@}*/
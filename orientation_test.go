@@ -0,0 +1,35 @@
+package zbar
+
+import (
+	"image"
+	"testing"
+)
+
+func TestEstimateOrientation(t *testing.T) {
+	// corners top left, bottom left, bottom right, top right of the symbol
+	var cases = []struct {
+		points []image.Point
+		want   ZBarOrientation
+	}{
+		{[]image.Point{{10, 10}, {10, 50}, {50, 50}, {50, 10}}, ZBAR_ORIENT_UP},
+		{[]image.Point{{50, 10}, {10, 10}, {10, 50}, {50, 50}}, ZBAR_ORIENT_RIGHT},
+		{[]image.Point{{50, 50}, {50, 10}, {10, 10}, {10, 50}}, ZBAR_ORIENT_DOWN},
+		{[]image.Point{{10, 50}, {50, 50}, {50, 10}, {10, 10}}, ZBAR_ORIENT_LEFT},
+		{[]image.Point{{10, 10}, {14, 50}, {55, 46}, {50, 3}}, ZBAR_ORIENT_UP},
+		{[]image.Point{{10, 10}, {50, 10}}, ZBAR_ORIENT_UNKNOWN},
+	}
+	for i, c := range cases {
+		if got := estimateOrientation(c.points); got != c.want {
+			t.Errorf("case %d: %v, want %v", i, got, c.want)
+		}
+	}
+
+	if o := ZBAR_ORIENT_UP.turn(-1); o != ZBAR_ORIENT_LEFT {
+		t.Errorf("turn = %v", o)
+	}
+	for o, deg := range map[ZBarOrientation]int{ZBAR_ORIENT_UP: 0, ZBAR_ORIENT_RIGHT: 270, ZBAR_ORIENT_DOWN: 180, ZBAR_ORIENT_LEFT: 90, ZBAR_ORIENT_UNKNOWN: 0} {
+		if got := o.Degrees(); got != deg {
+			t.Errorf("%v degrees = %d, want %d", o, got, deg)
+		}
+	}
+}
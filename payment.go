@@ -0,0 +1,31 @@
+package zbar
+
+import (
+	"fmt"
+
+	"github.com/zooyer/zbar/payment"
+)
+
+/*------------------------------------------------------------*/
+/** @name Payment QR codes
+ * Swiss QR-bills and SEPA credit transfers, see package payment
+ */
+/*@{*/
+
+/** parse the Swiss QR-bill payment part of a QR code symbol. */
+func (sym Symbol) SwissBill() (*payment.SwissBill, error) {
+	if sym.Type&ZBAR_SYMBOL != ZBAR_QRCODE {
+		return nil, fmt.Errorf("zbar: %v is not a QR code symbol", sym.Type)
+	}
+	return payment.ParseSwissBill(sym.Data)
+}
+
+/** parse the EPC069-12 SEPA credit transfer of a QR code symbol. */
+func (sym Symbol) SEPATransfer() (*payment.SEPATransfer, error) {
+	if sym.Type&ZBAR_SYMBOL != ZBAR_QRCODE {
+		return nil, fmt.Errorf("zbar: %v is not a QR code symbol", sym.Type)
+	}
+	return payment.ParseSEPATransfer(sym.Data)
+}
+
+/*@}*/
@@ -0,0 +1,100 @@
+package payment
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/** SEPA credit transfer from an EPC069-12 ("BCD") payload. */
+type SEPATransfer struct {
+	Version      string // "001" or "002"
+	CharacterSet int    // 1 UTF-8, 2 to 8 ISO 8859-1, -2, -4, -5, -7, -10, -15
+	BIC          string // optional since version 002
+	Name         string // beneficiary
+	IBAN         string
+	Amount       string // in EUR, "" if left to the payer
+	Purpose      string // ISO 20022 purpose code
+	Reference    string // structured (ISO 11649) creditor reference
+	Text         string // unstructured remittance information
+	Information  string // beneficiary to originator information
+}
+
+/** maximum size of an EPC069-12 payload in bytes. */
+const maxSEPA = 331
+
+/** parse an EPC069-12 SEPA credit transfer ("BCD") payload.
+ * trailing optional lines may be omitted.
+ */
+func ParseSEPATransfer(data string) (*SEPATransfer, error) {
+	var l = lines(data)
+	if len(data) > maxSEPA {
+		return nil, &FieldError{len(l), "payload", fmt.Sprintf("longer than %d bytes", maxSEPA)}
+	}
+	for len(l) > 12 && l[len(l)-1] == "" {
+		l = l[:len(l)-1]
+	}
+	switch {
+	case l[0] != "BCD":
+		return nil, &FieldError{1, "service tag", fmt.Sprintf("want BCD, got %q", l[0])}
+	case len(l) < 7:
+		return nil, &FieldError{len(l), "IBAN", fmt.Sprintf("want at least 7 lines, have %d", len(l))}
+	case len(l) > 12:
+		return nil, &FieldError{13, "payload", "more than 12 lines"}
+	}
+	for len(l) < 12 {
+		l = append(l, "")
+	}
+
+	var t = &SEPATransfer{Version: l[1], BIC: l[4], Name: l[5], IBAN: compact(l[6])}
+	if t.Version != "001" && t.Version != "002" {
+		return nil, &FieldError{2, "version", fmt.Sprintf("unsupported version %q", t.Version)}
+	}
+	var err error
+	if t.CharacterSet, err = strconv.Atoi(l[2]); err != nil || t.CharacterSet < 1 || t.CharacterSet > 8 {
+		return nil, &FieldError{3, "character set", fmt.Sprintf("invalid character set %q", l[2])}
+	}
+	if l[3] != "SCT" {
+		return nil, &FieldError{4, "identification", fmt.Sprintf("want SCT, got %q", l[3])}
+	}
+	switch {
+	case t.BIC == "" && t.Version == "001":
+		return nil, &FieldError{5, "BIC", "required in version 001"}
+	case t.BIC != "" && !validBIC(t.BIC):
+		return nil, &FieldError{5, "BIC", fmt.Sprintf("invalid BIC %q", t.BIC)}
+	case t.Name == "":
+		return nil, &FieldError{6, "name", "missing"}
+	case len([]rune(t.Name)) > 70:
+		return nil, &FieldError{6, "name", "longer than 70 characters"}
+	case !ValidIBAN(t.IBAN):
+		return nil, &FieldError{7, "IBAN", fmt.Sprintf("invalid IBAN %q", l[6])}
+	}
+
+	if amount := l[7]; amount != "" {
+		if !strings.HasPrefix(amount, "EUR") || !validAmount(amount[3:]) {
+			return nil, &FieldError{8, "amount", fmt.Sprintf("invalid amount %q", amount)}
+		}
+		t.Amount = amount[3:]
+	}
+	if t.Purpose = l[8]; t.Purpose != "" && (len(t.Purpose) != 4 || !isAlnum(strings.ToUpper(t.Purpose))) {
+		return nil, &FieldError{9, "purpose", fmt.Sprintf("invalid purpose code %q", t.Purpose)}
+	}
+
+	t.Reference, t.Text, t.Information = l[9], l[10], l[11]
+	switch {
+	case t.Reference != "" && t.Text != "":
+		return nil, &FieldError{11, "remittance information", "both structured and unstructured"}
+	case t.Reference != "" && (len(t.Reference) > 35 || !ValidCreditorReference(t.Reference)):
+		return nil, &FieldError{10, "remittance reference", fmt.Sprintf("invalid creditor reference %q", t.Reference)}
+	case len([]rune(t.Text)) > 140:
+		return nil, &FieldError{11, "remittance text", "longer than 140 characters"}
+	case len([]rune(t.Information)) > 70:
+		return nil, &FieldError{12, "information", "longer than 70 characters"}
+	}
+	return t, nil
+}
+
+/** 8 or 11 character ISO 9362 business identifier code. */
+func validBIC(bic string) bool {
+	return (len(bic) == 8 || len(bic) == 11) && isAlpha(bic[:6]) && isAlnum(bic)
+}
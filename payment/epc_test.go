@@ -0,0 +1,68 @@
+package payment
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSEPATransfer(t *testing.T) {
+	var data = "BCD\n002\n1\nSCT\nBPOTBEB1\nRed Cross Belgium\nBE72000000001616\nEUR1\nCHAR\n\nUrgency fund\nSample QR code"
+	tr, err := ParseSEPATransfer(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.Version != "002" || tr.CharacterSet != 1 || tr.BIC != "BPOTBEB1" || tr.Name != "Red Cross Belgium" ||
+		tr.IBAN != "BE72000000001616" || tr.Amount != "1" || tr.Purpose != "CHAR" ||
+		tr.Text != "Urgency fund" || tr.Information != "Sample QR code" {
+		t.Errorf("got %+v", tr)
+	}
+
+	// version 002 without BIC, omitted trailing lines, creditor reference
+	tr, err = ParseSEPATransfer("BCD\r\n002\r\n1\r\nSCT\r\n\r\nMax Mustermann\r\nDE89 3704 0044 0532 0130 00\r\nEUR12.30\r\n\r\nRF18539007547034")
+	if err != nil || tr.BIC != "" || tr.IBAN != "DE89370400440532013000" || tr.Amount != "12.30" || tr.Reference != "RF18539007547034" {
+		t.Errorf("002: %+v %v", tr, err)
+	}
+}
+
+func TestParseSEPATransferErrors(t *testing.T) {
+	var base = []string{"BCD", "001", "1", "SCT", "BPOTBEB1", "Red Cross Belgium", "BE72000000001616", "EUR1", "", "", "", ""}
+	for name, tc := range map[string]struct {
+		line  int
+		value string
+		want  int
+	}{
+		"service tag":  {0, "BCE", 1},
+		"version":      {1, "003", 2},
+		"charset":      {2, "9", 3},
+		"identifier":   {3, "SCX", 4},
+		"bic required": {4, "", 5},
+		"bic":          {4, "BPOT1EB1", 5},
+		"name":         {5, "", 6},
+		"iban":         {6, "BE72000000001617", 7},
+		"amount":       {7, "EUR0", 8},
+		"currency":     {7, "CHF1", 8},
+		"purpose":      {8, "CHARITY", 9},
+		"reference":    {9, "RF19539007547034", 10},
+		"text":         {10, strings.Repeat("x", 141), 11},
+		"information":  {11, strings.Repeat("x", 71), 12},
+	} {
+		var l = append([]string(nil), base...)
+		l[tc.line] = tc.value
+		_, err := ParseSEPATransfer(strings.Join(l, "\n"))
+		if e, ok := err.(*FieldError); !ok || e.Line != tc.want {
+			t.Errorf("%s: %v, want error on line %d", name, err, tc.want)
+		}
+	}
+
+	var both = append([]string(nil), base...)
+	both[9], both[10] = "RF18539007547034", "text"
+	if _, err := ParseSEPATransfer(strings.Join(both, "\n")); err == nil {
+		t.Error("structured and unstructured remittance accepted")
+	}
+	if _, err := ParseSEPATransfer("BCD\n001\n1\nSCT"); err == nil {
+		t.Error("truncated payload accepted")
+	}
+	if _, err := ParseSEPATransfer(strings.Join(base, "\n") + strings.Repeat("x", 300)); err == nil {
+		t.Error("oversized payload accepted")
+	}
+}
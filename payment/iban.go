@@ -0,0 +1,142 @@
+/** Package payment parses QR code payment instructions: Swiss
+ * QR-bills (Swiss Payment Standards, "SPC") and SEPA credit
+ * transfers (EPC069-12, "BCD").
+ *
+ * both are line based formats; the parsers check every field against
+ * its standard, including the IBAN, QR reference (mod 10 recursive)
+ * and creditor reference (ISO 11649) check digits, and report the
+ * offending line.
+ */
+package payment
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+/** invalid payment instruction. */
+type FieldError struct {
+	Line  int // 1-based line number
+	Field string
+	Msg   string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("payment: line %d: %s: %s", e.Line, e.Field, e.Msg)
+}
+
+/** IBAN lengths of the countries that are checked strictly. */
+var ibanLengths = map[string]int{
+	"AT": 20, "BE": 16, "CH": 21, "DE": 22, "ES": 24, "FI": 18,
+	"FR": 27, "GB": 22, "IE": 22, "IT": 27, "LI": 21, "LU": 20,
+	"NL": 18, "PT": 25,
+}
+
+/** remove spaces and upper case. */
+func compact(s string) string {
+	return strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+}
+
+/** verify an IBAN: structure, known country lengths and the ISO 7064
+ * mod 97-10 check digits.  spaces are ignored.
+ */
+func ValidIBAN(iban string) bool {
+	iban = compact(iban)
+	if len(iban) < 15 || len(iban) > 34 || !isAlpha(iban[:2]) || !isDigits(iban[2:4]) || !isAlnum(iban) {
+		return false
+	}
+	if n, ok := ibanLengths[iban[:2]]; ok && len(iban) != n {
+		return false
+	}
+	return mod97(iban[4:]+iban[:4]) == 1
+}
+
+/** Swiss QR-IBAN: a CH or LI IBAN whose institution ID is in the
+ * range 30000 to 31999 reserved for QR reference payments.
+ */
+func IsQRIBAN(iban string) bool {
+	iban = compact(iban)
+	return ValidIBAN(iban) && (iban[:2] == "CH" || iban[:2] == "LI") && iban[4] == '3' && (iban[5] == '0' || iban[5] == '1')
+}
+
+/** verify an ISO 11649 creditor reference ("RF" + 2 check digits +
+ * up to 21 characters).  spaces are ignored.
+ */
+func ValidCreditorReference(ref string) bool {
+	ref = compact(ref)
+	if len(ref) < 5 || len(ref) > 25 || ref[:2] != "RF" || !isDigits(ref[2:4]) || !isAlnum(ref) {
+		return false
+	}
+	return mod97(ref[4:]+ref[:4]) == 1
+}
+
+/** mod 10 recursive check digit of a Swiss QR reference. */
+func QRReferenceCheckDigit(digits string) byte {
+	var table = [10]int{0, 9, 4, 6, 8, 2, 7, 1, 3, 5}
+	var carry = 0
+	for i := 0; i < len(digits); i++ {
+		carry = table[(carry+int(digits[i]-'0'))%10]
+	}
+	return byte('0' + (10-carry)%10)
+}
+
+/** verify a Swiss QR reference: 27 digits, the last a mod 10
+ * recursive check digit.  spaces are ignored.
+ */
+func ValidQRReference(ref string) bool {
+	ref = compact(ref)
+	return len(ref) == 27 && isDigits(ref) && QRReferenceCheckDigit(ref[:26]) == ref[26]
+}
+
+/** ISO 7064 mod 97 of an alphanumeric string, letters as 10 to 35. */
+func mod97(s string) int64 {
+	var digits strings.Builder
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c >= 'A' && c <= 'Z' {
+			fmt.Fprintf(&digits, "%d", c-'A'+10)
+		} else {
+			digits.WriteByte(c)
+		}
+	}
+	var n, _ = new(big.Int).SetString(digits.String(), 10)
+	return new(big.Int).Mod(n, big.NewInt(97)).Int64()
+}
+
+/** decimal amount with at most 2 decimals within 0.01 to
+ * 999999999.99.
+ */
+func validAmount(amount string) bool {
+	var whole, frac, dot = strings.Cut(amount, ".")
+	if !isDigits(whole) || len(whole) > 9 || dot && (len(frac) == 0 || len(frac) > 2 || !isDigits(frac)) {
+		return false
+	}
+	return strings.Trim(whole+frac, "0") != ""
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isAlpha(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'A' || s[i] > 'Z' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isAlnum(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if (s[i] < '0' || s[i] > '9') && (s[i] < 'A' || s[i] > 'Z') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package payment
+
+import "testing"
+
+func TestValidIBAN(t *testing.T) {
+	for iban, want := range map[string]bool{
+		"CH9300762011623852957":      true,
+		"CH93 0076 2011 6238 5295 7": true,
+		"ch9300762011623852957":      true,
+		"DE89370400440532013000":     true,
+		"BE72000000001616":           true,
+		"CH9300762011623852958":      false,
+		"CH930076201162385295":       false,
+		"DE8937040044053201300":      false,
+		"XX00":                       false,
+		"CH93-0076-2011-6238-5295-7": false,
+	} {
+		if got := ValidIBAN(iban); got != want {
+			t.Errorf("%s: %v, want %v", iban, got, want)
+		}
+	}
+
+	if !IsQRIBAN("CH4431999123000889012") || IsQRIBAN("CH9300762011623852957") {
+		t.Error("QR-IBAN detection")
+	}
+}
+
+func TestReferences(t *testing.T) {
+	if !ValidQRReference("21 00000 00003 13947 14300 09017") || ValidQRReference("210000000003139471430009018") {
+		t.Error("QR reference")
+	}
+	if c := QRReferenceCheckDigit("21000000000313947143000901"); c != '7' {
+		t.Errorf("check digit %c", c)
+	}
+	if !ValidCreditorReference("RF18 5390 0754 7034") || ValidCreditorReference("RF19539007547034") ||
+		ValidCreditorReference("XX18539007547034") {
+		t.Error("creditor reference")
+	}
+}
+
+func TestValidAmount(t *testing.T) {
+	for amount, want := range map[string]bool{
+		"1949.75": true, "1": true, "0.01": true, "999999999.99": true,
+		"0.00": false, "1.": false, "1.234": false, "1000000000": false, "-1": false, "1,5": false,
+	} {
+		if got := validAmount(amount); got != want {
+			t.Errorf("%s: %v, want %v", amount, got, want)
+		}
+	}
+}
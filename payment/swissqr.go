@@ -0,0 +1,221 @@
+package payment
+
+import (
+	"fmt"
+	"strings"
+)
+
+/** QR-bill reference type. */
+const (
+	RefQRR  = "QRR"  /**< QR reference, requires a QR-IBAN */
+	RefSCOR = "SCOR" /**< ISO 11649 creditor reference */
+	RefNON  = "NON"  /**< without reference */
+)
+
+/** QR-bill party address.
+ * structured addresses ("S") use Street, BuildingNumber, PostalCode
+ * and Town; combined addresses ("K") use Line1 and Line2.
+ */
+type Address struct {
+	Type           string // "S" or "K"
+	Name           string
+	Street         string
+	BuildingNumber string
+	PostalCode     string
+	Town           string
+	Line1          string
+	Line2          string
+	Country        string // ISO 3166-1 alpha-2
+}
+
+/** Swiss QR-bill payment part. */
+type SwissBill struct {
+	Version       string // eg "0200"
+	IBAN          string
+	QRIBAN        bool
+	Creditor      Address
+	Amount        string // "" if left to the payer
+	Currency      string // "CHF" or "EUR"
+	Debtor        *Address
+	ReferenceType string // RefQRR, RefSCOR or RefNON
+	Reference     string
+	Message       string // unstructured message
+	BillInfo      string // structured bill information, eg "//S1/10/..."
+	Alternatives  []string
+}
+
+/** maximum field lengths. */
+const (
+	maxName     = 70
+	maxStreet   = 70
+	maxBuilding = 16
+	maxPostal   = 16
+	maxTown     = 35
+	maxMessage  = 140
+)
+
+/** split a payload into lines, accepting CRLF and LF. */
+func lines(data string) []string {
+	return strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n")
+}
+
+/** parse a Swiss QR-bill ("SPC") payload. */
+func ParseSwissBill(data string) (*SwissBill, error) {
+	var l = lines(data)
+	if len(l) < 31 {
+		if len(l) == 0 || l[0] != "SPC" {
+			return nil, &FieldError{1, "QRType", "not a QR-bill"}
+		}
+		return nil, &FieldError{len(l), "Trailer", fmt.Sprintf("want at least 31 lines, have %d", len(l))}
+	}
+	// trailing empty lines after the last used field are tolerated
+	for len(l) > 31 && l[len(l)-1] == "" {
+		l = l[:len(l)-1]
+	}
+	if len(l) > 34 {
+		return nil, &FieldError{35, "AltPmtInf", "more than 2 alternative schemes"}
+	}
+
+	var b = &SwissBill{Version: l[1]}
+	switch {
+	case l[0] != "SPC":
+		return nil, &FieldError{1, "QRType", fmt.Sprintf("want SPC, got %q", l[0])}
+	case len(b.Version) != 4 || !strings.HasPrefix(b.Version, "02") || !isDigits(b.Version):
+		return nil, &FieldError{2, "Version", fmt.Sprintf("unsupported version %q", b.Version)}
+	case l[2] != "1":
+		return nil, &FieldError{3, "Coding", fmt.Sprintf("unsupported coding type %q", l[2])}
+	}
+
+	b.IBAN = compact(l[3])
+	if !ValidIBAN(b.IBAN) || b.IBAN[:2] != "CH" && b.IBAN[:2] != "LI" {
+		return nil, &FieldError{4, "IBAN", fmt.Sprintf("invalid CH or LI IBAN %q", l[3])}
+	}
+	b.QRIBAN = IsQRIBAN(b.IBAN)
+
+	creditor, err := address(l[4:11], 5, true)
+	if err != nil {
+		return nil, err
+	}
+	b.Creditor = *creditor
+	if ultimate, err := address(l[11:18], 12, false); err != nil {
+		return nil, err
+	} else if ultimate != nil {
+		return nil, &FieldError{12, "UltmtCdtr", "reserved for future use, must be empty"}
+	}
+
+	if b.Amount = l[18]; b.Amount != "" && !validAmount(b.Amount) {
+		return nil, &FieldError{19, "Amt", fmt.Sprintf("invalid amount %q", b.Amount)}
+	}
+	if b.Currency = l[19]; b.Currency != "CHF" && b.Currency != "EUR" {
+		return nil, &FieldError{20, "Ccy", fmt.Sprintf("want CHF or EUR, got %q", b.Currency)}
+	}
+	if b.Debtor, err = address(l[20:27], 21, false); err != nil {
+		return nil, err
+	}
+
+	b.ReferenceType, b.Reference = l[27], compact(l[28])
+	if err = b.checkReference(); err != nil {
+		return nil, err
+	}
+
+	if l[30] != "EPD" {
+		return nil, &FieldError{31, "Trailer", fmt.Sprintf("want EPD, got %q", l[30])}
+	}
+	b.Message = l[29]
+	if len(l) > 31 {
+		b.BillInfo = l[31]
+	}
+	if len([]rune(b.Message))+len([]rune(b.BillInfo)) > maxMessage {
+		return nil, &FieldError{30, "Ustrd", fmt.Sprintf("message and bill information longer than %d characters", maxMessage)}
+	}
+	for i := 32; i < len(l); i++ {
+		if len([]rune(l[i])) > 100 {
+			return nil, &FieldError{i + 1, "AltPmt", "longer than 100 characters"}
+		}
+		if l[i] != "" {
+			b.Alternatives = append(b.Alternatives, l[i])
+		}
+	}
+	return b, nil
+}
+
+/** reference type and reference checks, including the QR-IBAN rule. */
+func (b *SwissBill) checkReference() error {
+	switch b.ReferenceType {
+	case RefQRR:
+		if !b.QRIBAN {
+			return &FieldError{28, "Tp", "QR reference requires a QR-IBAN"}
+		}
+		if !ValidQRReference(b.Reference) {
+			return &FieldError{29, "Ref", fmt.Sprintf("invalid QR reference %q", b.Reference)}
+		}
+	case RefSCOR, RefNON:
+		if b.QRIBAN {
+			return &FieldError{28, "Tp", "QR-IBAN requires a QR reference"}
+		}
+		if b.ReferenceType == RefSCOR && !ValidCreditorReference(b.Reference) {
+			return &FieldError{29, "Ref", fmt.Sprintf("invalid creditor reference %q", b.Reference)}
+		}
+		if b.ReferenceType == RefNON && b.Reference != "" {
+			return &FieldError{29, "Ref", "must be empty for reference type NON"}
+		}
+	default:
+		return &FieldError{28, "Tp", fmt.Sprintf("unknown reference type %q", b.ReferenceType)}
+	}
+	return nil
+}
+
+/** parse the 7 address lines starting at line first.
+ * @returns nil for an all empty optional address
+ */
+func address(l []string, first int, required bool) (*Address, error) {
+	if !required && strings.Join(l, "") == "" {
+		return nil, nil
+	}
+	var a = &Address{Type: l[0], Name: l[1], Country: l[6]}
+	var check = func(i int, field string, max int, mandatory bool) error {
+		switch {
+		case mandatory && l[i] == "":
+			return &FieldError{first + i, field, "missing"}
+		case len([]rune(l[i])) > max:
+			return &FieldError{first + i, field, fmt.Sprintf("longer than %d characters", max)}
+		}
+		return nil
+	}
+
+	var err error
+	switch a.Type {
+	case "S":
+		a.Street, a.BuildingNumber, a.PostalCode, a.Town = l[2], l[3], l[4], l[5]
+		for _, c := range []struct {
+			i         int
+			field     string
+			max       int
+			mandatory bool
+		}{{2, "StrtNmOrAdrLine1", maxStreet, false}, {3, "BldgNbOrAdrLine2", maxBuilding, false}, {4, "PstCd", maxPostal, true}, {5, "TwnNm", maxTown, true}} {
+			if err = check(c.i, c.field, c.max, c.mandatory); err != nil {
+				return nil, err
+			}
+		}
+	case "K":
+		a.Line1, a.Line2 = l[2], l[3]
+		if err = check(2, "StrtNmOrAdrLine1", maxStreet, false); err != nil {
+			return nil, err
+		}
+		if err = check(3, "BldgNbOrAdrLine2", maxStreet, true); err != nil {
+			return nil, err
+		}
+		if l[4] != "" || l[5] != "" {
+			return nil, &FieldError{first + 4, "PstCd", "must be empty for combined addresses"}
+		}
+	default:
+		return nil, &FieldError{first, "AdrTp", fmt.Sprintf("want S or K, got %q", a.Type)}
+	}
+	if err = check(1, "Name", maxName, true); err != nil {
+		return nil, err
+	}
+	if len(a.Country) != 2 || !isAlpha(a.Country) {
+		return nil, &FieldError{first + 6, "Ctry", fmt.Sprintf("invalid country code %q", a.Country)}
+	}
+	return a, nil
+}
@@ -0,0 +1,98 @@
+package payment
+
+import (
+	"strings"
+	"testing"
+)
+
+/** QR-bill with a QR-IBAN and QR reference, in the line layout of
+ * the Swiss Payment Standards examples.
+ */
+var qrrBill = []string{
+	"SPC", "0200", "1",
+	"CH4431999123000889012",
+	"S", "Robert Schneider AG", "Rue du Lac", "1268", "2501", "Biel", "CH",
+	"", "", "", "", "", "", "",
+	"1949.75", "CHF",
+	"S", "Pia-Maria Rutschmann-Schnyder", "Grosse Marktgasse", "28", "9400", "Rorschach", "CH",
+	"QRR", "210000000003139471430009017",
+	"Order of 15 June 2020", "EPD",
+	"//S1/10/10201409/11/200701/20/140.000-53/30/102673831/31/200615/32/7.7/33/7.7:139.40/40/0:30",
+	"Name AV1: UV;UltraPay005;12345",
+}
+
+func bill(edit func([]string)) string {
+	var l = append([]string(nil), qrrBill...)
+	if edit != nil {
+		edit(l)
+	}
+	return strings.Join(l, "\r\n")
+}
+
+func TestParseSwissBill(t *testing.T) {
+	b, err := ParseSwissBill(bill(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !b.QRIBAN || b.Creditor.Name != "Robert Schneider AG" || b.Creditor.BuildingNumber != "1268" ||
+		b.Amount != "1949.75" || b.Currency != "CHF" || b.Debtor == nil || b.Debtor.Town != "Rorschach" ||
+		b.ReferenceType != RefQRR || b.Message != "Order of 15 June 2020" ||
+		!strings.HasPrefix(b.BillInfo, "//S1/") || len(b.Alternatives) != 1 {
+		t.Errorf("got %+v", b)
+	}
+
+	// SCOR with a regular IBAN, open amount, no debtor, combined creditor address
+	b, err = ParseSwissBill(bill(func(l []string) {
+		l[3] = "CH93 0076 2011 6238 5295 7"
+		copy(l[4:11], []string{"K", "Robert Schneider AG", "Rue du Lac 1268", "2501 Biel", "", "", "CH"})
+		l[18] = ""
+		copy(l[20:27], make([]string, 7))
+		l[27], l[28] = RefSCOR, "RF18539007547034"
+	}) + "\n")
+	if err != nil || b.QRIBAN || b.Creditor.Type != "K" || b.Creditor.Line2 != "2501 Biel" ||
+		b.Amount != "" || b.Debtor != nil || b.Reference != "RF18539007547034" {
+		t.Errorf("scor: %+v %v", b, err)
+	}
+
+	// minimal 31 line payload
+	b, err = ParseSwissBill(strings.Join(qrrBill[:31], "\n"))
+	if err != nil || b.BillInfo != "" || b.Alternatives != nil {
+		t.Errorf("31 lines: %+v %v", b, err)
+	}
+}
+
+func TestParseSwissBillErrors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		edit func([]string)
+		line int
+	}{
+		"qr type":      {func(l []string) { l[0] = "SPD" }, 1},
+		"version":      {func(l []string) { l[1] = "0100" }, 2},
+		"coding":       {func(l []string) { l[2] = "2" }, 3},
+		"iban":         {func(l []string) { l[3] = "DE89370400440532013000" }, 4},
+		"address type": {func(l []string) { l[4] = "X" }, 5},
+		"name":         {func(l []string) { l[5] = "" }, 6},
+		"town":         {func(l []string) { l[9] = "" }, 10},
+		"building":     {func(l []string) { l[7] = strings.Repeat("1", 17) }, 8},
+		"country":      {func(l []string) { l[10] = "CHE" }, 11},
+		"ultimate":     {func(l []string) { l[12] = "x" }, 12},
+		"amount":       {func(l []string) { l[18] = "1949.755" }, 19},
+		"currency":     {func(l []string) { l[19] = "USD" }, 20},
+		"debtor":       {func(l []string) { l[26] = "" }, 27},
+		"ref type":     {func(l []string) { l[27] = "ABC" }, 28},
+		"qrr check":    {func(l []string) { l[28] = "210000000003139471430009018" }, 29},
+		"qr-iban scor": {func(l []string) { l[27], l[28] = RefSCOR, "RF18539007547034" }, 28},
+		"qrr plain":    {func(l []string) { l[3] = "CH9300762011623852957" }, 28},
+		"trailer":      {func(l []string) { l[30] = "EOD" }, 31},
+		"message":      {func(l []string) { l[29] = strings.Repeat("x", 100) }, 30},
+		"combined zip": {func(l []string) { l[4] = "K" }, 9},
+	} {
+		_, err := ParseSwissBill(bill(tc.edit))
+		if e, ok := err.(*FieldError); !ok || e.Line != tc.line {
+			t.Errorf("%s: %v, want error on line %d", name, err, tc.line)
+		}
+	}
+	if _, err := ParseSwissBill("SPC\n0200\n1"); err == nil {
+		t.Error("truncated bill accepted")
+	}
+}
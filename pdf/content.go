@@ -0,0 +1,226 @@
+package pdf
+
+import (
+	"bytes"
+	"image"
+	"math"
+)
+
+/** axis aligned rectangle in PDF user space units (1/72 inch),
+ * origin at the bottom left of the page.
+ */
+type Rect struct {
+	X0, Y0, X1, Y1 float64
+}
+
+/** image placed on a page. */
+type Image struct {
+	Page  int         // 0-based page index
+	Name  string      // resource name the image was drawn with
+	Rect  Rect        // bounding box of the placed image on the page
+	Image image.Image // decoded samples, nil if Err is set
+	Err   error       // decoding error, eg ErrUnsupported
+}
+
+/** maximum nesting of form XObjects. */
+const maxForms = 16
+
+/** affine transformation [a b c d e f] as used by the "cm" operator;
+ * a point is transformed as [x y 1] x M.
+ */
+type matrix [6]float64
+
+var identity = matrix{1, 0, 0, 1, 0, 0}
+
+/** m followed by n. */
+func (m matrix) mul(n matrix) matrix {
+	return matrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+func (m matrix) apply(x, y float64) (float64, float64) {
+	return x*m[0] + y*m[2] + m[4], x*m[1] + y*m[3] + m[5]
+}
+
+/** bounding box of the unit square, which image XObjects are drawn into. */
+func (m matrix) unitRect() Rect {
+	var r = Rect{X0: math.Inf(1), Y0: math.Inf(1), X1: math.Inf(-1), Y1: math.Inf(-1)}
+	for _, p := range [4][2]float64{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+		x, y := m.apply(p[0], p[1])
+		r.X0, r.Y0 = math.Min(r.X0, x), math.Min(r.Y0, y)
+		r.X1, r.Y1 = math.Max(r.X1, x), math.Max(r.Y1, y)
+	}
+	return r
+}
+
+func toMatrix(o object) (matrix, bool) {
+	a, ok := o.(array)
+	if !ok || len(a) != 6 {
+		return identity, false
+	}
+	var m matrix
+	for i := range m {
+		if m[i], ok = toFloat(a[i]); !ok {
+			return identity, false
+		}
+	}
+	return m, true
+}
+
+/** images drawn by a page, in drawing order.
+ * @param page 0-based page index
+ */
+func (d *Document) Images(page int) ([]Image, error) {
+	if page < 0 || page >= len(d.pages) {
+		return nil, ErrNoPages
+	}
+
+	var p = d.pages[page]
+	var res, _ = d.resolve(p["Resources"]).(dict)
+
+	var content []byte
+	switch c := d.resolve(p["Contents"]).(type) {
+	case *stream:
+		data, err := decodeStream(c)
+		if err != nil {
+			return nil, err
+		}
+		content = data
+	case array:
+		// content may be split at any token boundary across streams
+		for _, part := range c {
+			if s, ok := d.resolve(part).(*stream); ok {
+				data, err := decodeStream(s)
+				if err != nil {
+					return nil, err
+				}
+				content = append(append(content, data...), '\n')
+			}
+		}
+	}
+
+	var w = &walker{doc: d, page: page, forms: make(map[*stream]bool)}
+	w.run(content, res, identity, 0)
+	return w.images, nil
+}
+
+/** content stream interpreter tracking just the transformation matrix. */
+type walker struct {
+	doc    *Document
+	page   int
+	images []Image
+	forms  map[*stream]bool
+}
+
+func (w *walker) run(content []byte, res dict, ctm matrix, depth int) {
+	var xobjects, _ = w.doc.resolve(res["XObject"]).(dict)
+	var stack []matrix
+	var operands []object
+
+	var l = &lexer{buf: content}
+	for !l.eof() {
+		obj, err := l.object()
+		if err != nil {
+			// skip the offending byte and carry on, like viewers do
+			l.pos++
+			operands = operands[:0]
+			continue
+		}
+
+		op, ok := obj.(keyword)
+		if !ok {
+			operands = append(operands, obj)
+			continue
+		}
+
+		switch op {
+		case "q":
+			stack = append(stack, ctm)
+		case "Q":
+			if n := len(stack); n > 0 {
+				ctm, stack = stack[n-1], stack[:n-1]
+			}
+		case "cm":
+			if len(operands) == 6 {
+				if m, ok := toMatrix(array(operands)); ok {
+					ctm = m.mul(ctm)
+				}
+			}
+		case "Do":
+			if len(operands) == 1 {
+				if n, ok := operands[0].(name); ok {
+					w.draw(n, xobjects, res, ctm, depth)
+				}
+			}
+		case "BI":
+			l.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+}
+
+/** handle "Do" for an image or form XObject. */
+func (w *walker) draw(n name, xobjects, res dict, ctm matrix, depth int) {
+	var r = xobjects[n]
+	s, ok := w.doc.resolve(r).(*stream)
+	if !ok {
+		return
+	}
+
+	switch s.dict.name("Subtype") {
+	case "Image":
+		var img = Image{Page: w.page, Name: string(n), Rect: ctm.unitRect()}
+		img.Image, img.Err = w.doc.image(r, s)
+		w.images = append(w.images, img)
+
+	case "Form":
+		if depth >= maxForms || w.forms[s] {
+			return
+		}
+		data, err := decodeStream(s)
+		if err != nil {
+			return
+		}
+		var formRes, _ = w.doc.resolve(s.dict["Resources"]).(dict)
+		if formRes == nil {
+			formRes = res
+		}
+		var m, _ = toMatrix(w.doc.resolve(s.dict["Matrix"]))
+
+		w.forms[s] = true
+		w.run(data, formRes, m.mul(ctm), depth+1)
+		delete(w.forms, s)
+	}
+}
+
+/** skip an inline image "BI <dict> ID <data> EI".
+ * the data length is not recorded, so scan for "EI" surrounded by
+ * white space.
+ */
+func (l *lexer) skipInlineImage() {
+	var i = bytes.Index(l.buf[l.pos:], []byte("ID"))
+	if i < 0 {
+		l.pos = len(l.buf)
+		return
+	}
+	l.pos += i + 3
+
+	for l.pos < len(l.buf) {
+		i = bytes.Index(l.buf[l.pos:], []byte("EI"))
+		if i < 0 {
+			l.pos = len(l.buf)
+			return
+		}
+		var at = l.pos + i
+		l.pos = at + 2
+		if at > 0 && isSpace(l.buf[at-1]) && (l.pos == len(l.buf) || isSpace(l.buf[l.pos])) {
+			return
+		}
+	}
+}
@@ -0,0 +1,301 @@
+/** Package pdf extracts the raster images embedded in PDF documents.
+ *
+ * this is not a PDF renderer: pages are not rasterized, only image
+ * XObjects drawn by the page content (directly or through form
+ * XObjects) are decoded, together with their placement on the page.
+ * that is enough for scanned documents and shipping labels, where the
+ * barcode is part of an embedded image.  DCT (JPEG), Flate and CCITT
+ * G3/G4 compressed images are supported; encrypted documents are not.
+ */
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strconv"
+)
+
+var (
+	/** the document is encrypted. */
+	ErrEncrypted = errors.New("pdf: encrypted documents are not supported")
+	/** no page tree could be located. */
+	ErrNoPages = errors.New("pdf: no page tree found")
+	/** an image uses a filter or color space that is not supported. */
+	ErrUnsupported = errors.New("pdf: unsupported image encoding")
+	/** image sample data ends more than a few rows early. */
+	ErrTruncated = errors.New("pdf: truncated image data")
+)
+
+/** maximum page tree depth and number of reference hops followed. */
+const maxResolve = 32
+
+/** parsed document.
+ * all objects are located by scanning the file for "N G obj"
+ * definitions rather than trusting the cross-reference table, which
+ * is frequently damaged in generated labels; later definitions (from
+ * incremental updates) replace earlier ones.
+ */
+type Document struct {
+	MaxPixels int // maximum number of pixels of an image, DefaultMaxPixels if 0
+
+	objects map[int64]object
+	pages   []dict
+	images  map[int64]*decoded
+}
+
+var objHeader = regexp.MustCompile(`(\d+)[ \t\r\n\f\x00]+(\d+)[ \t\r\n\f\x00]+obj\b`)
+
+/** parse a complete PDF file. */
+func Parse(data []byte) (*Document, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, " \t\r\n"), []byte("%PDF-")) {
+		return nil, &SyntaxError{Offset: 0, Msg: "missing %PDF header"}
+	}
+
+	var d = &Document{
+		objects: make(map[int64]object),
+		images:  make(map[int64]*decoded),
+	}
+
+	var trailer dict
+	var objStms []*stream
+	for pos := 0; pos < len(data); {
+		var loc = objHeader.FindSubmatchIndex(data[pos:])
+		var next = len(data)
+		if loc != nil {
+			next = pos + loc[0]
+		}
+
+		// trailer dictionaries between objects (classic xref tables)
+		if t := findTrailer(data[pos:next], int64(pos)); t != nil {
+			trailer = t
+		}
+		if loc == nil {
+			break
+		}
+
+		num, _ := strconv.ParseInt(string(data[pos+loc[2]:pos+loc[3]]), 10, 64)
+		var l = &lexer{buf: data, pos: pos + loc[1]}
+		obj, err := l.indirect()
+		if err != nil {
+			// damaged object, resume scanning after its header
+			pos += loc[1]
+			continue
+		}
+		pos = l.pos
+
+		d.objects[num] = obj
+		if s, ok := obj.(*stream); ok {
+			switch s.dict.name("Type") {
+			case "ObjStm":
+				objStms = append(objStms, s)
+			case "XRef":
+				trailer = s.dict
+			}
+		}
+	}
+
+	for _, s := range objStms {
+		d.loadObjStm(s)
+	}
+
+	if trailer != nil && trailer["Encrypt"] != nil {
+		return nil, ErrEncrypted
+	}
+
+	var root, _ = d.resolve(trailer["Root"]).(dict)
+	if root == nil {
+		root = d.findCatalog()
+	}
+	if root == nil {
+		return nil, ErrNoPages
+	}
+
+	pages, _ := d.resolve(root["Pages"]).(dict)
+	if pages == nil {
+		return nil, ErrNoPages
+	}
+	var seen = map[int64]bool{}
+	if r, ok := root["Pages"].(ref); ok {
+		seen[r.num] = true
+	}
+	d.walkPages(pages, dict{}, seen, 0)
+
+	return d, nil
+}
+
+/** number of pages in the document. */
+func (d *Document) NumPages() int {
+	return len(d.pages)
+}
+
+/** parse the value of an indirect object following its "obj" keyword. */
+func (l *lexer) indirect() (object, error) {
+	obj, err := l.object()
+	if err != nil {
+		return nil, err
+	}
+
+	var save = l.pos
+	l.skipSpace()
+	if !bytes.HasPrefix(l.buf[l.pos:], []byte("stream")) {
+		l.pos = save
+		return obj, nil
+	}
+	d, ok := obj.(dict)
+	if !ok {
+		return nil, l.errorf("stream without dictionary")
+	}
+
+	// the keyword is followed by CRLF or LF
+	l.pos += len("stream")
+	if l.pos < len(l.buf) && l.buf[l.pos] == '\r' {
+		l.pos++
+	}
+	if l.pos < len(l.buf) && l.buf[l.pos] == '\n' {
+		l.pos++
+	}
+	var start = l.pos
+
+	// trust a direct /Length when it lands on "endstream"
+	if n, ok := d["Length"].(int64); ok && n >= 0 && int64(start)+n <= int64(len(l.buf)) {
+		var end = start + int(n)
+		var tail = bytes.TrimLeft(l.buf[end:], " \t\r\n")
+		if bytes.HasPrefix(tail, []byte("endstream")) {
+			l.pos = len(l.buf) - len(tail) + len("endstream")
+			return &stream{dict: d, data: l.buf[start:end]}, nil
+		}
+	}
+
+	var i = bytes.Index(l.buf[start:], []byte("endstream"))
+	if i < 0 {
+		return nil, l.errorf("unterminated stream")
+	}
+	var data = l.buf[start : start+i]
+	if bytes.HasSuffix(data, []byte("\r\n")) {
+		data = data[:len(data)-2]
+	} else if bytes.HasSuffix(data, []byte("\n")) || bytes.HasSuffix(data, []byte("\r")) {
+		data = data[:len(data)-1]
+	}
+	l.pos = start + i + len("endstream")
+
+	return &stream{dict: d, data: data}, nil
+}
+
+/** find the last "trailer << ... >>" dictionary in buf. */
+func findTrailer(buf []byte, base int64) dict {
+	var i = bytes.LastIndex(buf, []byte("trailer"))
+	if i < 0 {
+		return nil
+	}
+
+	var l = &lexer{buf: buf, pos: i + len("trailer"), base: base}
+	d, _ := l.object()
+	t, _ := d.(dict)
+	return t
+}
+
+/** load the objects stored in an object stream.
+ * objects defined directly in the file take precedence.
+ */
+func (d *Document) loadObjStm(s *stream) {
+	data, err := decodeStream(s)
+	if err != nil {
+		return
+	}
+	n, _ := toInt(s.dict["N"])
+	first, _ := toInt(s.dict["First"])
+	if first < 0 || first > int64(len(data)) {
+		return
+	}
+
+	var header = &lexer{buf: data[:first]}
+	for i := int64(0); i < n; i++ {
+		num, err1 := header.object()
+		off, err2 := header.object()
+		if err1 != nil || err2 != nil {
+			return
+		}
+		objNum, _ := num.(int64)
+		objOff, _ := off.(int64)
+		if objOff < 0 || first+objOff >= int64(len(data)) {
+			continue
+		}
+		if _, ok := d.objects[objNum]; ok {
+			continue
+		}
+
+		var l = &lexer{buf: data, pos: int(first + objOff)}
+		if obj, err := l.object(); err == nil {
+			d.objects[objNum] = obj
+		}
+	}
+}
+
+/** follow indirect references. */
+func (d *Document) resolve(o object) object {
+	for i := 0; i < maxResolve; i++ {
+		r, ok := o.(ref)
+		if !ok {
+			return o
+		}
+		o = d.objects[r.num]
+	}
+	return nil
+}
+
+func (d *Document) findCatalog() dict {
+	for _, o := range d.objects {
+		if c, ok := o.(dict); ok && c.name("Type") == "Catalog" {
+			return c
+		}
+	}
+	return nil
+}
+
+/** attributes inherited from parent page tree nodes. */
+var inheritable = []name{"Resources", "MediaBox", "CropBox", "Rotate"}
+
+/** collect the leaf pages of a page tree in document order.
+ * seen holds the objects already visited: nodes listed again, whether
+ * shared or forming a cycle, are skipped.
+ */
+func (d *Document) walkPages(node dict, inherited dict, seen map[int64]bool, depth int) {
+	if depth > maxResolve {
+		return
+	}
+
+	var attrs = dict{}
+	for _, key := range inheritable {
+		if v, ok := node[key]; ok {
+			attrs[key] = v
+		} else if v, ok := inherited[key]; ok {
+			attrs[key] = v
+		}
+	}
+
+	kids, isTree := d.resolve(node["Kids"]).(array)
+	if !isTree || node.name("Type") == "Page" {
+		var page = dict{}
+		for k, v := range node {
+			page[k] = v
+		}
+		for k, v := range attrs {
+			page[k] = v
+		}
+		d.pages = append(d.pages, page)
+		return
+	}
+
+	for _, kid := range kids {
+		if r, ok := kid.(ref); ok {
+			if seen[r.num] {
+				continue
+			}
+			seen[r.num] = true
+		}
+		if k, ok := d.resolve(kid).(dict); ok {
+			d.walkPages(k, attrs, seen, depth+1)
+		}
+	}
+}
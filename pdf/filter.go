@@ -0,0 +1,236 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"encoding/ascii85"
+	"errors"
+	"io"
+)
+
+/** maximum decoded size of a single stream, guards against zip bombs. */
+const maxStreamSize = 256 << 20
+
+/** decode a stream whose filters are all generic (no image codecs). */
+func decodeStream(s *stream) ([]byte, error) {
+	data, filter, _, err := applyFilters(s)
+	if err != nil {
+		return nil, err
+	}
+	if filter != "" {
+		return nil, ErrUnsupported
+	}
+	return data, nil
+}
+
+/** apply the generic filters of a stream in order.
+ * decoding stops at the first image codec filter (DCTDecode,
+ * CCITTFaxDecode, ...), which must be the last one in the chain.
+ * @returns the decoded data, the remaining image filter (or "") and
+ * its decode parameters
+ */
+func applyFilters(s *stream) ([]byte, name, dict, error) {
+	var filters []name
+	var parms []dict
+
+	switch f := s.dict["Filter"].(type) {
+	case name:
+		filters = []name{f}
+		p, _ := s.dict["DecodeParms"].(dict)
+		parms = []dict{p}
+	case array:
+		var pa, _ = s.dict["DecodeParms"].(array)
+		for i, o := range f {
+			n, _ := o.(name)
+			filters = append(filters, n)
+			var p dict
+			if i < len(pa) {
+				p, _ = pa[i].(dict)
+			}
+			parms = append(parms, p)
+		}
+	}
+
+	var data = s.data
+	for i, f := range filters {
+		var err error
+		switch f {
+		case "FlateDecode", "Fl":
+			if data, err = inflate(data); err == nil {
+				data, err = unpredict(data, parms[i])
+			}
+		case "ASCIIHexDecode", "AHx":
+			data, err = asciiHex(data)
+		case "ASCII85Decode", "A85":
+			data, err = ascii85Decode(data)
+		case "RunLengthDecode", "RL":
+			data = runLength(data)
+		case "DCTDecode", "DCT", "CCITTFaxDecode", "CCF", "JPXDecode", "JBIG2Decode":
+			if i != len(filters)-1 {
+				return nil, "", nil, ErrUnsupported
+			}
+			return data, f, parms[i], nil
+		default:
+			return nil, "", nil, ErrUnsupported
+		}
+		if err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	return data, "", nil, nil
+}
+
+/** zlib (or, for broken writers, raw deflate) decompression.
+ * a truncated stream yields the data decoded so far.
+ */
+func inflate(data []byte) ([]byte, error) {
+	var r io.ReadCloser
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		r = flate.NewReader(bytes.NewReader(data))
+	}
+	defer r.Close()
+
+	out, err := io.ReadAll(io.LimitReader(r, maxStreamSize))
+	if err == io.ErrUnexpectedEOF && len(out) > 0 {
+		err = nil
+	}
+	return out, err
+}
+
+/** undo TIFF (2) or PNG (10-15) predictors. */
+func unpredict(data []byte, parms dict) ([]byte, error) {
+	predictor, _ := toInt(parms["Predictor"])
+	if predictor <= 1 {
+		return data, nil
+	}
+
+	var colors, bpc, columns int64 = 1, 8, 1
+	if v, ok := toInt(parms["Colors"]); ok && v > 0 {
+		colors = v
+	}
+	if v, ok := toInt(parms["BitsPerComponent"]); ok && v > 0 {
+		bpc = v
+	}
+	if v, ok := toInt(parms["Columns"]); ok && v > 0 {
+		columns = v
+	}
+	var bpp = int((colors*bpc + 7) / 8)
+	var rowLen = int((colors*bpc*columns + 7) / 8)
+	if rowLen <= 0 || rowLen > maxStreamSize {
+		return nil, ErrUnsupported
+	}
+
+	if predictor == 2 {
+		if bpc != 8 {
+			return nil, ErrUnsupported
+		}
+		for row := 0; row+rowLen <= len(data); row += rowLen {
+			for i := row + bpp; i < row+rowLen; i++ {
+				data[i] += data[i-bpp]
+			}
+		}
+		return data, nil
+	}
+
+	// PNG predictors: every row starts with its own filter type byte
+	var out = make([]byte, 0, len(data)/(rowLen+1)*rowLen)
+	var prev = make([]byte, rowLen)
+	for pos := 0; pos+rowLen+1 <= len(data); pos += rowLen + 1 {
+		var ft = data[pos]
+		var cur = data[pos+1 : pos+1+rowLen]
+		for i := range cur {
+			var a, c byte
+			if i >= bpp {
+				a, c = cur[i-bpp], prev[i-bpp]
+			}
+			var b = prev[i]
+			switch ft {
+			case 0:
+			case 1:
+				cur[i] += a
+			case 2:
+				cur[i] += b
+			case 3:
+				cur[i] += byte((int(a) + int(b)) / 2)
+			case 4:
+				cur[i] += paeth(a, b, c)
+			default:
+				return nil, errors.New("pdf: invalid png predictor")
+			}
+		}
+		out = append(out, cur...)
+		prev = cur
+	}
+
+	return out, nil
+}
+
+func paeth(a, b, c byte) byte {
+	var p = int(a) + int(b) - int(c)
+	var pa, pb, pc = abs(p - int(a)), abs(p - int(b)), abs(p - int(c))
+	if pa <= pb && pa <= pc {
+		return a
+	}
+	if pb <= pc {
+		return b
+	}
+	return c
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func asciiHex(data []byte) ([]byte, error) {
+	var l = &lexer{buf: append(append([]byte{}, data...), '>')}
+	s, err := l.hex()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s.(string)), nil
+}
+
+func ascii85Decode(data []byte) ([]byte, error) {
+	data = bytes.TrimPrefix(bytes.TrimSpace(data), []byte("<~"))
+	if i := bytes.Index(data, []byte("~>")); i >= 0 {
+		data = data[:i]
+	}
+
+	var out = make([]byte, 4*len(data)+4)
+	n, _, err := ascii85.Decode(out, data, true)
+	if err != nil {
+		return nil, err
+	}
+	return out[:n], nil
+}
+
+func runLength(data []byte) []byte {
+	var out []byte
+	for i := 0; i < len(data); {
+		var n = int(data[i])
+		i++
+		switch {
+		case n < 128:
+			var end = i + n + 1
+			if end > len(data) {
+				end = len(data)
+			}
+			out = append(out, data[i:end]...)
+			i = end
+		case n > 128:
+			if i < len(data) {
+				out = append(out, bytes.Repeat(data[i:i+1], 257-n)...)
+			}
+			i++
+		default:
+			return out
+		}
+	}
+	return out
+}
@@ -0,0 +1,336 @@
+package pdf
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+
+	"golang.org/x/image/ccitt"
+)
+
+/** default maximum number of pixels of a single image, enough for an
+ * A4 page at 400 dpi or a 4x6 inch label at 600 dpi.
+ */
+const DefaultMaxPixels = 1 << 24
+
+/** number of missing rows at the end of the sample data that are
+ * tolerated and padded.
+ */
+const maxMissingRows = 8
+
+/** check image dimensions against a pixel limit without overflowing. */
+func validSize(width, height, limit int64) bool {
+	return width > 0 && height > 0 && width <= limit && height <= limit/width
+}
+
+/** pixel limit of the document's images. */
+func (d *Document) maxPixels() int64 {
+	if d.MaxPixels > 0 {
+		return int64(d.MaxPixels)
+	}
+	return DefaultMaxPixels
+}
+
+/** a decoded image XObject, cached by object number. */
+type decoded struct {
+	img image.Image
+	err error
+}
+
+/** decode an image XObject, caching by object number when r is a reference. */
+func (d *Document) image(r object, s *stream) (image.Image, error) {
+	var num int64 = -1
+	if rr, ok := r.(ref); ok {
+		num = rr.num
+		if c, ok := d.images[num]; ok {
+			return c.img, c.err
+		}
+	}
+
+	img, err := d.decodeImage(s)
+	if num >= 0 {
+		d.images[num] = &decoded{img: img, err: err}
+	}
+	return img, err
+}
+
+/** color space reduced to what is needed to expand samples. */
+type colorSpace struct {
+	components  int
+	kind        name          // DeviceGray, DeviceRGB, DeviceCMYK or Indexed
+	palette     color.Palette // Indexed only
+	subtractive bool          // Separation: 1.0 means full ink
+}
+
+func (d *Document) decodeImage(s *stream) (image.Image, error) {
+	width, _ := toInt(d.resolve(s.dict["Width"]))
+	height, _ := toInt(d.resolve(s.dict["Height"]))
+	if !validSize(width, height, d.maxPixels()) {
+		return nil, ErrUnsupported
+	}
+
+	data, filter, parms, err := applyFilters(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var mask, _ = d.resolve(s.dict["ImageMask"]).(bool)
+	var bpc int64 = 1
+	if !mask {
+		bpc, _ = toInt(d.resolve(s.dict["BitsPerComponent"]))
+	}
+	var decode, _ = d.resolve(s.dict["Decode"]).(array)
+
+	switch filter {
+	case "DCTDecode", "DCT":
+		return jpeg.Decode(bytes.NewReader(data))
+
+	case "CCITTFaxDecode", "CCF":
+		if data, err = decodeCCITT(data, parms, int(width), int(height)); err != nil {
+			return nil, err
+		}
+		bpc = 1
+
+	case "":
+
+	default:
+		return nil, ErrUnsupported
+	}
+
+	var cs = colorSpace{components: 1, kind: "DeviceGray"}
+	if !mask {
+		if cs, err = d.colorSpace(s.dict["ColorSpace"], 0); err != nil {
+			return nil, err
+		}
+	}
+
+	return samples(data, int(width), int(height), int(bpc), cs, decodeInverted(decode), d.maxPixels())
+}
+
+/** report whether a /Decode array swaps the sample range, eg [1 0]. */
+func decodeInverted(decode array) bool {
+	if len(decode) < 2 {
+		return false
+	}
+	lo, _ := toFloat(decode[0])
+	hi, _ := toFloat(decode[1])
+	return lo > hi
+}
+
+func (d *Document) colorSpace(o object, depth int) (colorSpace, error) {
+	o = d.resolve(o)
+	if depth > 2 {
+		return colorSpace{}, ErrUnsupported
+	}
+
+	var family name
+	var a array
+	switch v := o.(type) {
+	case name:
+		family = v
+	case array:
+		if len(v) == 0 {
+			return colorSpace{}, ErrUnsupported
+		}
+		a = v
+		family, _ = d.resolve(v[0]).(name)
+	}
+
+	switch family {
+	case "DeviceGray", "CalGray", "G":
+		return colorSpace{components: 1, kind: "DeviceGray"}, nil
+	case "DeviceRGB", "CalRGB", "RGB":
+		return colorSpace{components: 3, kind: "DeviceRGB"}, nil
+	case "DeviceCMYK", "CMYK":
+		return colorSpace{components: 4, kind: "DeviceCMYK"}, nil
+	case "Separation":
+		return colorSpace{components: 1, kind: "DeviceGray", subtractive: true}, nil
+
+	case "ICCBased":
+		if len(a) < 2 {
+			return colorSpace{}, ErrUnsupported
+		}
+		profile, ok := d.resolve(a[1]).(*stream)
+		if !ok {
+			return colorSpace{}, ErrUnsupported
+		}
+		switch n, _ := toInt(d.resolve(profile.dict["N"])); n {
+		case 1:
+			return colorSpace{components: 1, kind: "DeviceGray"}, nil
+		case 3:
+			return colorSpace{components: 3, kind: "DeviceRGB"}, nil
+		case 4:
+			return colorSpace{components: 4, kind: "DeviceCMYK"}, nil
+		}
+
+	case "Indexed", "I":
+		if len(a) < 4 {
+			return colorSpace{}, ErrUnsupported
+		}
+		base, err := d.colorSpace(a[1], depth+1)
+		if err != nil || base.kind == "Indexed" {
+			return colorSpace{}, ErrUnsupported
+		}
+		hival, _ := toInt(d.resolve(a[2]))
+
+		var lookup []byte
+		switch t := d.resolve(a[3]).(type) {
+		case string:
+			lookup = []byte(t)
+		case *stream:
+			if lookup, err = decodeStream(t); err != nil {
+				return colorSpace{}, err
+			}
+		}
+
+		var palette color.Palette
+		for i := 0; i <= int(hival) && i < 256; i++ {
+			var off = i * base.components
+			if off+base.components > len(lookup) {
+				break
+			}
+			palette = append(palette, base.color(lookup[off:off+base.components]))
+		}
+		if len(palette) == 0 {
+			return colorSpace{}, ErrUnsupported
+		}
+		return colorSpace{components: 1, kind: "Indexed", palette: palette}, nil
+	}
+
+	return colorSpace{}, ErrUnsupported
+}
+
+/** color of one pixel given 8-bit components. */
+func (cs colorSpace) color(c []byte) color.Color {
+	switch cs.kind {
+	case "DeviceRGB":
+		return color.RGBA{R: c[0], G: c[1], B: c[2], A: 0xff}
+	case "DeviceCMYK":
+		return color.CMYK{C: c[0], M: c[1], Y: c[2], K: c[3]}
+	}
+	if cs.subtractive {
+		return color.Gray{Y: 255 - c[0]}
+	}
+	return color.Gray{Y: c[0]}
+}
+
+/** expand raw samples into an image of at most limit pixels.
+ * rows are byte aligned; components of less than 8 bits are scaled up
+ * and 16 bit components are truncated to their high byte.  data that
+ * ends up to maxMissingRows early is padded, the visible part may
+ * still contain a readable code.
+ */
+func samples(data []byte, width, height, bpc int, cs colorSpace, invert bool, limit int64) (image.Image, error) {
+	switch bpc {
+	case 1, 2, 4, 8, 16:
+	default:
+		return nil, ErrUnsupported
+	}
+
+	if !validSize(int64(width), int64(height), limit) {
+		return nil, ErrUnsupported
+	}
+	// at most 8 bytes per pixel, ie 4 components of 16 bits
+	var rowLen = (width*cs.components*bpc + 7) / 8
+	if int64(rowLen) > 8*limit/int64(height) {
+		return nil, ErrUnsupported
+	}
+	if len(data) < rowLen*height {
+		if len(data) < rowLen*(height-maxMissingRows) {
+			return nil, ErrTruncated
+		}
+		data = append(data, make([]byte, rowLen*height-len(data))...)
+	}
+
+	if cs.kind == "DeviceGray" && bpc == 8 && !invert && !cs.subtractive {
+		var gray = image.NewGray(image.Rect(0, 0, width, height))
+		for y := 0; y < height; y++ {
+			copy(gray.Pix[y*gray.Stride:], data[y*rowLen:(y+1)*rowLen])
+		}
+		return gray, nil
+	}
+
+	var max = 1<<uint(bpc) - 1
+	var sample = func(row []byte, i int) int {
+		switch bpc {
+		case 8:
+			return int(row[i])
+		case 16:
+			return int(row[2*i])<<8 | int(row[2*i+1])
+		}
+		var bit = i * bpc
+		return int(row[bit/8]>>uint(8-bpc-bit%8)) & max
+	}
+
+	var rect = image.Rect(0, 0, width, height)
+	var comps = make([]byte, cs.components)
+	var img interface {
+		image.Image
+		Set(x, y int, c color.Color)
+	}
+
+	switch cs.kind {
+	case "DeviceGray":
+		img = image.NewGray(rect)
+	case "DeviceRGB":
+		img = image.NewRGBA(rect)
+	case "DeviceCMYK":
+		img = image.NewCMYK(rect)
+	case "Indexed":
+		img = image.NewPaletted(rect, cs.palette)
+	}
+
+	for y := 0; y < height; y++ {
+		var row = data[y*rowLen : (y+1)*rowLen]
+		for x := 0; x < width; x++ {
+			for c := range comps {
+				var v = sample(row, x*cs.components+c)
+				if invert {
+					v = max - v
+				}
+				if cs.kind == "Indexed" {
+					comps[c] = byte(v)
+				} else {
+					comps[c] = byte(v * 255 / max)
+				}
+			}
+
+			if p, ok := img.(*image.Paletted); ok {
+				var i = comps[0]
+				if int(i) >= len(cs.palette) {
+					i = byte(len(cs.palette) - 1)
+				}
+				p.SetColorIndex(x, y, i)
+			} else {
+				img.Set(x, y, cs.color(comps))
+			}
+		}
+	}
+
+	return img, nil
+}
+
+/** decode CCITT G3/G4 data into 1 bit per pixel rows, 1 meaning white. */
+func decodeCCITT(data []byte, parms dict, width, height int) ([]byte, error) {
+	var k, _ = toInt(parms["K"])
+	if k > 0 {
+		// mixed one and two dimensional G3 is not supported by the decoder
+		return nil, ErrUnsupported
+	}
+	var sf = ccitt.Group3
+	if k < 0 {
+		sf = ccitt.Group4
+	}
+
+	var blackIs1, _ = parms["BlackIs1"].(bool)
+	var align, _ = parms["EncodedByteAlign"].(bool)
+
+	var r = ccitt.NewReader(bytes.NewReader(data), ccitt.MSB, sf, width, height, &ccitt.Options{Align: align, Invert: blackIs1})
+	var out, err = io.ReadAll(r)
+	if err != nil && len(out) == 0 {
+		return nil, err
+	}
+	return out, nil
+}
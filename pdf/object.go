@@ -0,0 +1,363 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+/** PDF object model.
+ * objects are represented by plain Go values:
+ *   null -> nil, boolean -> bool, integer -> int64, real -> float64,
+ *   string -> string (raw bytes), name -> name, array -> array,
+ *   dictionary -> dict, indirect reference -> ref, stream -> *stream.
+ * bare words that are not one of the above (operators in content
+ * streams, "obj", "stream", ...) are returned as keyword.
+ */
+type object interface{}
+
+type name string
+
+type keyword string
+
+type array []object
+
+type dict map[name]object
+
+type ref struct {
+	num, gen int64
+}
+
+/** stream object, data is still encoded with the stream's filters. */
+type stream struct {
+	dict dict
+	data []byte
+}
+
+/** syntax error at a byte offset of the input. */
+type SyntaxError struct {
+	Offset int64
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("pdf: syntax error at offset %d: %s", e.Offset, e.Msg)
+}
+
+/** maximum nesting of arrays and dictionaries. */
+const maxDepth = 64
+
+/** tokenizer and object parser over an in-memory buffer.
+ * base is the offset of buf in the file, used for error positions.
+ */
+type lexer struct {
+	buf  []byte
+	pos  int
+	base int64
+}
+
+func isSpace(c byte) bool {
+	return c == 0 || c == '\t' || c == '\n' || c == '\f' || c == '\r' || c == ' '
+}
+
+func isDelim(c byte) bool {
+	return c == '(' || c == ')' || c == '<' || c == '>' || c == '[' || c == ']' ||
+		c == '{' || c == '}' || c == '/' || c == '%'
+}
+
+func (l *lexer) errorf(format string, args ...interface{}) error {
+	return &SyntaxError{Offset: l.base + int64(l.pos), Msg: fmt.Sprintf(format, args...)}
+}
+
+/** skip white space and comments. */
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.buf) {
+		var c = l.buf[l.pos]
+		if c == '%' {
+			for l.pos < len(l.buf) && l.buf[l.pos] != '\n' && l.buf[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		if !isSpace(c) {
+			return
+		}
+		l.pos++
+	}
+}
+
+func (l *lexer) eof() bool {
+	l.skipSpace()
+	return l.pos >= len(l.buf)
+}
+
+/** read a bare word (number or keyword) at the current position. */
+func (l *lexer) word() []byte {
+	var start = l.pos
+	for l.pos < len(l.buf) && !isSpace(l.buf[l.pos]) && !isDelim(l.buf[l.pos]) {
+		l.pos++
+	}
+	return l.buf[start:l.pos]
+}
+
+/** parse the next object.
+ * integers followed by "<gen> R" are returned as ref.
+ */
+func (l *lexer) object() (object, error) {
+	return l.parse(0)
+}
+
+func (l *lexer) parse(depth int) (object, error) {
+	if depth > maxDepth {
+		return nil, l.errorf("nesting too deep")
+	}
+	if l.eof() {
+		return nil, l.errorf("unexpected end of data")
+	}
+
+	switch c := l.buf[l.pos]; c {
+	case '/':
+		l.pos++
+		return l.name(), nil
+
+	case '(':
+		l.pos++
+		return l.literal()
+
+	case '<':
+		if l.pos+1 < len(l.buf) && l.buf[l.pos+1] == '<' {
+			l.pos += 2
+			return l.dict(depth)
+		}
+		l.pos++
+		return l.hex()
+
+	case '[':
+		l.pos++
+		var a = array{}
+		for {
+			if l.eof() {
+				return nil, l.errorf("unterminated array")
+			}
+			if l.buf[l.pos] == ']' {
+				l.pos++
+				return a, nil
+			}
+			o, err := l.parse(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, o)
+		}
+
+	case ')', '>', ']', '{', '}':
+		l.pos++
+		return keyword(c), nil
+	}
+
+	var w = l.word()
+	if len(w) == 0 {
+		return nil, l.errorf("unexpected character %q", l.buf[l.pos])
+	}
+
+	switch string(w) {
+	case "null":
+		return nil, nil
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+
+	if i, err := strconv.ParseInt(string(w), 10, 64); err == nil {
+		// "<num> <gen> R" indirect reference
+		var save = l.pos
+		l.skipSpace()
+		if gen, err := strconv.ParseInt(string(l.word()), 10, 64); err == nil {
+			l.skipSpace()
+			if r := l.word(); len(r) == 1 && r[0] == 'R' {
+				return ref{num: i, gen: gen}, nil
+			}
+		}
+		l.pos = save
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(string(w), 64); err == nil {
+		return f, nil
+	}
+
+	return keyword(w), nil
+}
+
+func (l *lexer) name() name {
+	var w = l.word()
+	if bytes.IndexByte(w, '#') < 0 {
+		return name(w)
+	}
+
+	var b = make([]byte, 0, len(w))
+	for i := 0; i < len(w); i++ {
+		if w[i] == '#' && i+2 < len(w) {
+			if v, err := strconv.ParseUint(string(w[i+1:i+3]), 16, 8); err == nil {
+				b = append(b, byte(v))
+				i += 2
+				continue
+			}
+		}
+		b = append(b, w[i])
+	}
+	return name(b)
+}
+
+func (l *lexer) literal() (object, error) {
+	var b []byte
+	var nest = 1
+	for l.pos < len(l.buf) {
+		var c = l.buf[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			nest++
+		case ')':
+			if nest--; nest == 0 {
+				return string(b), nil
+			}
+		case '\\':
+			if l.pos >= len(l.buf) {
+				break
+			}
+			c = l.buf[l.pos]
+			l.pos++
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				// line continuation
+				if l.pos < len(l.buf) && l.buf[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			case '0', '1', '2', '3', '4', '5', '6', '7':
+				var v = int(c - '0')
+				for i := 0; i < 2 && l.pos < len(l.buf) && l.buf[l.pos] >= '0' && l.buf[l.pos] <= '7'; i++ {
+					v = v*8 + int(l.buf[l.pos]-'0')
+					l.pos++
+				}
+				c = byte(v)
+			}
+		}
+		b = append(b, c)
+	}
+
+	return nil, l.errorf("unterminated string")
+}
+
+func (l *lexer) hex() (object, error) {
+	var b []byte
+	var hi = -1
+	for l.pos < len(l.buf) {
+		var c = l.buf[l.pos]
+		l.pos++
+		if c == '>' {
+			if hi >= 0 {
+				b = append(b, byte(hi<<4))
+			}
+			return string(b), nil
+		}
+		if isSpace(c) {
+			continue
+		}
+
+		v, ok := unhex(c)
+		if !ok {
+			return nil, l.errorf("invalid hex string character %q", c)
+		}
+		if hi < 0 {
+			hi = v
+		} else {
+			b = append(b, byte(hi<<4|v))
+			hi = -1
+		}
+	}
+
+	return nil, l.errorf("unterminated hex string")
+}
+
+func unhex(c byte) (int, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0'), true
+	case c >= 'a' && c <= 'f':
+		return int(c-'a') + 10, true
+	case c >= 'A' && c <= 'F':
+		return int(c-'A') + 10, true
+	}
+	return 0, false
+}
+
+func (l *lexer) dict(depth int) (object, error) {
+	var d = dict{}
+	for {
+		if l.eof() {
+			return nil, l.errorf("unterminated dictionary")
+		}
+		if l.buf[l.pos] == '>' {
+			if l.pos+1 < len(l.buf) && l.buf[l.pos+1] == '>' {
+				l.pos += 2
+				return d, nil
+			}
+			return nil, l.errorf("unexpected '>' in dictionary")
+		}
+
+		key, err := l.parse(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		k, ok := key.(name)
+		if !ok {
+			return nil, l.errorf("dictionary key %v is not a name", key)
+		}
+		val, err := l.parse(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		d[k] = val
+	}
+}
+
+/** helpers for loosely typed access to dictionary values. */
+
+func (d dict) name(key name) name {
+	n, _ := d[key].(name)
+	return n
+}
+
+func toInt(o object) (int64, bool) {
+	switch v := o.(type) {
+	case int64:
+		return v, true
+	case float64:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+func toFloat(o object) (float64, bool) {
+	switch v := o.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
@@ -0,0 +1,232 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+/** assemble a PDF from object bodies; object n is objects[n-1]. */
+func buildPDF(objects ...string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	var offsets []int
+	for i, o := range objects {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+	var xref = buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+func streamObj(dict string, data []byte) string {
+	return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+}
+
+func deflate(data []byte) []byte {
+	var buf bytes.Buffer
+	var w = zlib.NewWriter(&buf)
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestImages(t *testing.T) {
+	// 4x2 gray image, flate compressed
+	var pixels = []byte{0, 255, 0, 255, 255, 0, 255, 0}
+
+	var jpg bytes.Buffer
+	var src = image.NewGray(image.Rect(0, 0, 8, 8))
+	for i := range src.Pix {
+		src.Pix[i] = 200
+	}
+	jpeg.Encode(&jpg, src, nil)
+
+	var doc = buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 7 0 R] /Count 2 /Resources << /XObject << /Im1 4 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 5 0 R >>",
+		streamObj("/Type /XObject /Subtype /Image /Width 4 /Height 2 /ColorSpace /DeviceGray /BitsPerComponent 8 /Filter /FlateDecode", deflate(pixels)),
+		streamObj("", []byte("q 200 0 0 100 50 60 cm /Im1 Do Q BI /W 1 /H 1 ID \x00 EI q 1 0 0 1 10 20 cm /Fm1 Do Q")),
+		streamObj("/Type /XObject /Subtype /Form /BBox [0 0 100 100] /Resources << /XObject << /Im2 8 0 R >> >>", []byte("20 0 0 10 0 0 cm /Im2 Do")),
+		"<< /Type /Page /Parent 2 0 R /Contents 9 0 R /Resources << /XObject << /Fm1 6 0 R >> >> >>",
+		streamObj("/Subtype /Image /Width 8 /Height 8 /ColorSpace /DeviceGray /BitsPerComponent 8 /Filter /DCTDecode", jpg.Bytes()),
+		streamObj("", []byte("/Fm1 Do")),
+	)
+
+	d, err := Parse(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.NumPages() != 2 {
+		t.Fatalf("pages = %d", d.NumPages())
+	}
+
+	images, err := d.Images(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(images) != 1 {
+		t.Fatalf("page 0: %d images", len(images))
+	}
+	var im = images[0]
+	if im.Err != nil {
+		t.Fatal(im.Err)
+	}
+	if im.Name != "Im1" || im.Rect != (Rect{50, 60, 250, 160}) {
+		t.Errorf("page 0 image = %s %+v", im.Name, im.Rect)
+	}
+	if g := color.GrayModel.Convert(im.Image.At(1, 0)).(color.Gray); g.Y != 255 {
+		t.Errorf("pixel (1,0) = %d", g.Y)
+	}
+
+	// page 1 draws the JPEG through a form XObject with inherited resources
+	images, err = d.Images(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(images) != 1 || images[0].Err != nil {
+		t.Fatalf("page 1: %+v", images)
+	}
+	if images[0].Page != 1 || images[0].Rect != (Rect{0, 0, 20, 10}) {
+		t.Errorf("page 1 image = %+v", images[0].Rect)
+	}
+	if images[0].Image.Bounds().Dx() != 8 {
+		t.Errorf("jpeg bounds = %v", images[0].Image.Bounds())
+	}
+}
+
+func TestSamples(t *testing.T) {
+	// 1 bit image mask rows are byte aligned: 0 paints black
+	img, err := samples([]byte{0x50, 0xa0}, 4, 2, 1, colorSpace{components: 1, kind: "DeviceGray"}, false, DefaultMaxPixels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gray = img.(*image.Gray)
+	var want = []byte{0, 255, 0, 255, 255, 0, 255, 0}
+	if !bytes.Equal(gray.Pix, want) {
+		t.Errorf("pix = %v, want %v", gray.Pix, want)
+	}
+}
+
+func TestPageTreeCycles(t *testing.T) {
+	// node 2 lists itself three times and node 3 twice
+	var doc = buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [2 0 R 2 0 R 2 0 R 3 0 R 4 0 R 3 0 R] /Count 2 >>",
+		"<< /Type /Page /Parent 2 0 R >>",
+		"<< /Type /Pages /Kids [4 0 R 2 0 R 5 0 R] >>",
+		"<< /Type /Page /Parent 4 0 R >>",
+	)
+	d, err := Parse(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.NumPages() != 2 {
+		t.Errorf("pages = %d", d.NumPages())
+	}
+}
+
+func TestOversizedImage(t *testing.T) {
+	// width*height wraps to 0 in 64 bits
+	var doc = buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R /Resources << /XObject << /Im1 5 0 R >> >> >>",
+		streamObj("", []byte("/Im1 Do")),
+		streamObj("/Subtype /Image /Width 4294967296 /Height 4294967296 /ColorSpace /DeviceGray /BitsPerComponent 8", []byte{0}),
+	)
+	d, err := Parse(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	images, err := d.Images(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(images) != 1 || images[0].Err != ErrUnsupported {
+		t.Errorf("images = %+v", images)
+	}
+
+	if _, err = samples(nil, 1<<20, 1<<20, 8, colorSpace{components: 1, kind: "DeviceGray"}, false, DefaultMaxPixels); err != ErrUnsupported {
+		t.Errorf("samples: %v", err)
+	}
+	if _, err = samples(nil, 1<<12, 1<<12, 16, colorSpace{components: 8, kind: "DeviceN"}, false, DefaultMaxPixels); err != ErrUnsupported {
+		t.Errorf("samples row length: %v", err)
+	}
+}
+
+func TestTruncatedImage(t *testing.T) {
+	// 8192x8192 RGB image with a single byte of data
+	var doc = buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R /Resources << /XObject << /Im1 5 0 R >> >> >>",
+		streamObj("", []byte("/Im1 Do")),
+		streamObj("/Subtype /Image /Width 8192 /Height 8192 /ColorSpace /DeviceRGB /BitsPerComponent 8", []byte{0}),
+	)
+	for limit, want := range map[int]error{0: ErrUnsupported, 1 << 26: ErrTruncated} {
+		d, err := Parse(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d.MaxPixels = limit
+		images, err := d.Images(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(images) != 1 || images[0].Err != want {
+			t.Errorf("limit %d: images = %+v", limit, images)
+		}
+	}
+
+	// a few missing rows are padded
+	var gray = colorSpace{components: 1, kind: "DeviceGray"}
+	img, err := samples(make([]byte, 4*15), 4, 20, 8, gray, false, DefaultMaxPixels)
+	if err != nil || img.Bounds().Dy() != 20 {
+		t.Errorf("padded: %v %v", img, err)
+	}
+	if _, err = samples(make([]byte, 4*11), 4, 20, 8, gray, false, DefaultMaxPixels); err != ErrTruncated {
+		t.Errorf("truncated: %v", err)
+	}
+}
+
+func TestPredictor(t *testing.T) {
+	// two rows of 3 bytes, PNG "Up" predictor on the second row
+	var data = []byte{0, 1, 2, 3, 2, 1, 1, 1}
+	out, err := unpredict(data, dict{"Predictor": int64(12), "Columns": int64(3)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out, []byte{1, 2, 3, 2, 3, 4}) {
+		t.Errorf("out = %v", out)
+	}
+}
+
+func TestLexer(t *testing.T) {
+	var l = &lexer{buf: []byte(`<< /A#20B (a\)b\051) /C [1 -2.5 3 0 R <4142>] /D true >>`)}
+	o, err := l.object()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d = o.(dict)
+	if d["A B"] != "a)b)" {
+		t.Errorf("A B = %q", d["A B"])
+	}
+	var a = d["C"].(array)
+	if a[0] != int64(1) || a[1] != -2.5 || a[2] != (ref{3, 0}) || a[3] != "AB" {
+		t.Errorf("C = %v", a)
+	}
+	if d["D"] != true {
+		t.Errorf("D = %v", d["D"])
+	}
+}
@@ -0,0 +1,71 @@
+package zbar
+
+import (
+	"io"
+
+	"github.com/zooyer/zbar/pdf"
+)
+
+/*------------------------------------------------------------*/
+/** @name PDF scanning
+ * scans the raster images embedded in PDF pages, see package pdf
+ */
+/*@{*/
+
+/** symbol found in an image embedded in a PDF page.
+ * Symbol.Points are in the pixel coordinates of the embedded image.
+ */
+type PDFSymbol struct {
+	Symbol
+	Page      int      // 0-based page index, also set as Symbol.Sequence
+	ImageName string   // XObject resource name of the scanned image
+	ImageRect pdf.Rect // placement of the image on the page, in points
+}
+
+/** scan every image embedded in a PDF document.
+ * images that cannot be decoded (eg JBIG2 or JPX compressed, larger
+ * than Profile.PDFMaxPixels or truncated) are skipped.
+ * @param stopAtFirst stop after the first page that yields any symbol
+ */
+func (s *Scanner) ScanPDF(r io.Reader, stopAtFirst bool) ([]PDFSymbol, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := pdf.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	doc.MaxPixels = s.profile.PDFMaxPixels
+
+	var symbols []PDFSymbol
+	for page := 0; page < doc.NumPages(); page++ {
+		images, err := doc.Images(page)
+		if err != nil {
+			return symbols, err
+		}
+
+		var found bool
+		for _, img := range images {
+			if img.Err != nil {
+				continue
+			}
+			syms, err := s.scanImage(img.Image, uint32(page))
+			if err != nil {
+				return symbols, err
+			}
+			for _, sym := range syms {
+				symbols = append(symbols, PDFSymbol{Symbol: sym, Page: page, ImageName: img.Name, ImageRect: img.Rect})
+				found = true
+			}
+		}
+
+		if found && stopAtFirst {
+			break
+		}
+	}
+
+	return symbols, nil
+}
+
+/*@}*/
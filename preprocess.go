@@ -0,0 +1,433 @@
+package zbar
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*------------------------------------------------------------*/
+/** @name Image preprocessing
+ * composable filters run on the 8-bit grayscale (Y800) samples before
+ * they are handed to the image scanner.  useful for dim, low contrast
+ * or noisy photos
+ */
+/*@{*/
+
+/** preprocessing stage.
+ * Apply returns the filtered image, which must have the same bounds
+ * as src; src itself is left unmodified as it may be owned by the
+ * caller of Scanner.Scan.
+ */
+type Preprocess interface {
+	Apply(src *image.Gray) *image.Gray
+}
+
+/** ordered list of stages. */
+type Pipeline []Preprocess
+
+/** run all stages in order. */
+func (p Pipeline) Apply(src *image.Gray) *image.Gray {
+	for _, stage := range p {
+		src = stage.Apply(src)
+	}
+	return src
+}
+
+/** convert an image to grayscale, using the first Grayscale stage if
+ * the pipeline has one and the default luminance conversion otherwise.
+ */
+func (p Pipeline) Gray(img image.Image) *image.Gray {
+	for _, stage := range p {
+		if g, ok := stage.(Grayscale); ok {
+			return g.Convert(img)
+		}
+	}
+	return toGray(img)
+}
+
+/** parse a stage specification of the form "name[=arg[,arg...]]".
+ * recognized stages and their optional arguments:
+ *   grayscale[=luma|r|g|b|min|max]
+ *   equalize
+ *   sauvola[=window[,k[,r]]]
+ *   unsharp[=radius[,amount]]
+ *   median[=size]
+ *   gamma=value
+ */
+func ParsePreprocess(spec string) (Preprocess, error) {
+	var stage, params = spec, ""
+	if i := strings.IndexByte(spec, '='); i >= 0 {
+		stage, params = spec[:i], spec[i+1:]
+	}
+	stage = strings.ToLower(strings.TrimSpace(stage))
+
+	var args []float64
+	if params != "" && stage != "grayscale" {
+		for _, f := range strings.Split(params, ",") {
+			v, err := strconv.ParseFloat(strings.TrimSpace(f), 64)
+			if err != nil {
+				return nil, fmt.Errorf("zbar: invalid preprocess %q: %v", spec, err)
+			}
+			args = append(args, v)
+		}
+	}
+	var arg = func(i int, def float64) float64 {
+		if i < len(args) {
+			return args[i]
+		}
+		return def
+	}
+
+	var p Preprocess
+	switch stage {
+	case "grayscale":
+		p = Grayscale{Channel: strings.ToLower(strings.TrimSpace(params))}
+	case "equalize":
+		p = Equalize{}
+	case "sauvola":
+		p = Sauvola{Window: int(arg(0, 15)), K: arg(1, 0.34), R: arg(2, 128)}
+	case "unsharp":
+		p = Unsharp{Radius: int(arg(0, 2)), Amount: arg(1, 1)}
+	case "median":
+		p = Median{Size: int(arg(0, 3))}
+	case "gamma":
+		if len(args) == 0 {
+			return nil, fmt.Errorf("zbar: invalid preprocess %q: missing value", spec)
+		}
+		p = Gamma{Gamma: args[0]}
+	default:
+		return nil, fmt.Errorf("zbar: unknown preprocess %q", spec)
+	}
+
+	if v, ok := p.(interface{ validate() error }); ok {
+		if err := v.validate(); err != nil {
+			return nil, fmt.Errorf("zbar: invalid preprocess %q: %v", spec, err)
+		}
+	}
+	return p, nil
+}
+
+/** color to grayscale conversion.
+ * Channel selects "luma" (default, ITU-R 601), a single "r", "g" or
+ * "b" channel, or the per pixel "min"/"max" of the channels; "min"
+ * gives colored bars on white the best contrast.  case is ignored.
+ * as a stage on an image that is already gray it does nothing.
+ */
+type Grayscale struct {
+	Channel string
+}
+
+func (g Grayscale) Apply(src *image.Gray) *image.Gray {
+	return src
+}
+
+func (g Grayscale) validate() error {
+	switch g.channel() {
+	case "", "luma", "r", "g", "b", "min", "max":
+		return nil
+	}
+	return fmt.Errorf("unknown channel %q", g.Channel)
+}
+
+/** convert img according to Channel. */
+func (g Grayscale) Convert(img image.Image) *image.Gray {
+	var channel = g.channel()
+	if _, ok := img.(*image.Gray); ok || channel == "" || channel == "luma" {
+		return toGray(img)
+	}
+
+	var b = img.Bounds()
+	var dst = image.NewGray(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		var row = dst.Pix[(y-b.Min.Y)*dst.Stride:]
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, gr, bl, _ := img.At(x, y).RGBA()
+			var v uint32
+			switch channel {
+			case "r":
+				v = r
+			case "g":
+				v = gr
+			case "b":
+				v = bl
+			case "min":
+				v = min(r, gr, bl)
+			case "max":
+				v = max(r, gr, bl)
+			}
+			row[x-b.Min.X] = uint8(v >> 8)
+		}
+	}
+	return dst
+}
+
+func (g Grayscale) channel() string {
+	return strings.ToLower(g.Channel)
+}
+
+/** global histogram equalization. */
+type Equalize struct{}
+
+func (Equalize) Apply(src *image.Gray) *image.Gray {
+	var hist [256]int
+	forEachRow(src, func(row []uint8) {
+		for _, v := range row {
+			hist[v]++
+		}
+	})
+
+	var total = src.Rect.Dx() * src.Rect.Dy()
+	var cdf, cdfMin int
+	var lut [256]uint8
+	for i, n := range hist {
+		cdf += n
+		if cdfMin == 0 {
+			cdfMin = cdf
+		}
+		if total > cdfMin {
+			lut[i] = uint8((cdf - cdfMin) * 255 / (total - cdfMin))
+		} else {
+			lut[i] = uint8(i)
+		}
+	}
+
+	return applyLUT(src, &lut)
+}
+
+/** Sauvola adaptive thresholding.
+ * a pixel is set white if it is brighter than
+ *   mean * (1 + K * (stddev / R - 1))
+ * over the Window x Window neighbourhood, black otherwise.
+ * typical values are Window 15..31, K 0.2..0.5 and R 128.
+ */
+type Sauvola struct {
+	Window int
+	K      float64
+	R      float64
+}
+
+func (s Sauvola) validate() error {
+	if s.Window < 3 || s.R <= 0 {
+		return fmt.Errorf("window must be >= 3 and r > 0")
+	}
+	return nil
+}
+
+func (s Sauvola) Apply(src *image.Gray) *image.Gray {
+	var w, h = src.Rect.Dx(), src.Rect.Dy()
+	if w == 0 || h == 0 || s.Window < 3 || s.R <= 0 {
+		return src
+	}
+
+	// integral images of values and squared values, with a zero border
+	var stride = w + 1
+	var sum = make([]float64, stride*(h+1))
+	var sq = make([]float64, stride*(h+1))
+	for y := 0; y < h; y++ {
+		var row = src.Pix[y*src.Stride:]
+		var rowSum, rowSq float64
+		for x := 0; x < w; x++ {
+			var v = float64(row[x])
+			rowSum += v
+			rowSq += v * v
+			sum[(y+1)*stride+x+1] = sum[y*stride+x+1] + rowSum
+			sq[(y+1)*stride+x+1] = sq[y*stride+x+1] + rowSq
+		}
+	}
+
+	var half = s.Window / 2
+	var dst = image.NewGray(src.Rect)
+	for y := 0; y < h; y++ {
+		var y0, y1 = clampInt(y-half, 0, h), clampInt(y+half+1, 0, h)
+		for x := 0; x < w; x++ {
+			var x0, x1 = clampInt(x-half, 0, w), clampInt(x+half+1, 0, w)
+			var n = float64((x1 - x0) * (y1 - y0))
+			var total = sum[y1*stride+x1] - sum[y0*stride+x1] - sum[y1*stride+x0] + sum[y0*stride+x0]
+			var total2 = sq[y1*stride+x1] - sq[y0*stride+x1] - sq[y1*stride+x0] + sq[y0*stride+x0]
+			var mean = total / n
+			var std = math.Sqrt(math.Max(total2/n-mean*mean, 0))
+			var threshold = mean * (1 + s.K*(std/s.R-1))
+			if float64(src.Pix[y*src.Stride+x]) > threshold {
+				dst.Pix[y*dst.Stride+x] = 255
+			}
+		}
+	}
+	return dst
+}
+
+/** unsharp mask: src + Amount * (src - blur(src)), using a box blur
+ * of the given Radius.
+ */
+type Unsharp struct {
+	Radius int
+	Amount float64
+}
+
+func (u Unsharp) validate() error {
+	if u.Radius < 1 {
+		return fmt.Errorf("radius must be >= 1")
+	}
+	return nil
+}
+
+func (u Unsharp) Apply(src *image.Gray) *image.Gray {
+	if u.Radius < 1 {
+		return src
+	}
+
+	var blur = boxBlur(src, u.Radius)
+	var dst = image.NewGray(src.Rect)
+	var w, h = src.Rect.Dx(), src.Rect.Dy()
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var v = float64(src.Pix[y*src.Stride+x])
+			var b = float64(blur.Pix[y*blur.Stride+x])
+			dst.Pix[y*dst.Stride+x] = clampByte(v + u.Amount*(v-b))
+		}
+	}
+	return dst
+}
+
+/** separable box blur with edge clamping. */
+func boxBlur(src *image.Gray, radius int) *image.Gray {
+	var w, h = src.Rect.Dx(), src.Rect.Dy()
+	var dst = image.NewGray(src.Rect)
+	if w == 0 || h == 0 {
+		return dst
+	}
+	var tmp = make([]int, w*h)
+	var n = 2*radius + 1
+
+	for y := 0; y < h; y++ {
+		var row = src.Pix[y*src.Stride:]
+		var acc int
+		for i := -radius; i <= radius; i++ {
+			acc += int(row[clampInt(i, 0, w-1)])
+		}
+		for x := 0; x < w; x++ {
+			tmp[y*w+x] = acc
+			acc += int(row[clampInt(x+radius+1, 0, w-1)]) - int(row[clampInt(x-radius, 0, w-1)])
+		}
+	}
+
+	for x := 0; x < w; x++ {
+		var acc int
+		for i := -radius; i <= radius; i++ {
+			acc += tmp[clampInt(i, 0, h-1)*w+x]
+		}
+		for y := 0; y < h; y++ {
+			dst.Pix[y*dst.Stride+x] = uint8(acc / (n * n))
+			acc += tmp[clampInt(y+radius+1, 0, h-1)*w+x] - tmp[clampInt(y-radius, 0, h-1)*w+x]
+		}
+	}
+	return dst
+}
+
+/** median filter over a Size x Size window (odd, 3..9). */
+type Median struct {
+	Size int
+}
+
+func (m Median) validate() error {
+	if m.Size < 3 || m.Size > 9 || m.Size%2 == 0 {
+		return fmt.Errorf("size must be odd and between 3 and 9")
+	}
+	return nil
+}
+
+func (m Median) Apply(src *image.Gray) *image.Gray {
+	if m.validate() != nil {
+		return src
+	}
+
+	var w, h = src.Rect.Dx(), src.Rect.Dy()
+	var half = m.Size / 2
+	var window = make([]int, 0, m.Size*m.Size)
+	var dst = image.NewGray(src.Rect)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			window = window[:0]
+			for dy := -half; dy <= half; dy++ {
+				var row = src.Pix[clampInt(y+dy, 0, h-1)*src.Stride:]
+				for dx := -half; dx <= half; dx++ {
+					window = append(window, int(row[clampInt(x+dx, 0, w-1)]))
+				}
+			}
+			sort.Ints(window)
+			dst.Pix[y*dst.Stride+x] = uint8(window[len(window)/2])
+		}
+	}
+	return dst
+}
+
+/** gamma correction: out = 255 * (in / 255) ^ Gamma.
+ * values below 1 brighten dark images, values above 1 darken.
+ */
+type Gamma struct {
+	Gamma float64
+}
+
+func (g Gamma) validate() error {
+	if g.Gamma <= 0 {
+		return fmt.Errorf("gamma must be > 0")
+	}
+	return nil
+}
+
+func (g Gamma) Apply(src *image.Gray) *image.Gray {
+	if g.Gamma <= 0 {
+		return src
+	}
+
+	var lut [256]uint8
+	for i := range lut {
+		lut[i] = clampByte(255 * math.Pow(float64(i)/255, g.Gamma))
+	}
+	return applyLUT(src, &lut)
+}
+
+/** map every sample of src through lut into a new image. */
+func applyLUT(src *image.Gray, lut *[256]uint8) *image.Gray {
+	var dst = image.NewGray(src.Rect)
+	var w = src.Rect.Dx()
+	for y := 0; y < src.Rect.Dy(); y++ {
+		var in, out = src.Pix[y*src.Stride : y*src.Stride+w], dst.Pix[y*dst.Stride:]
+		for i, v := range in {
+			out[i] = lut[v]
+		}
+	}
+	return dst
+}
+
+func forEachRow(img *image.Gray, fn func(row []uint8)) {
+	var w = img.Rect.Dx()
+	for y := 0; y < img.Rect.Dy(); y++ {
+		fn(img.Pix[y*img.Stride : y*img.Stride+w])
+	}
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+func clampByte(v float64) uint8 {
+	if v <= 0 {
+		return 0
+	}
+	if v >= 255 {
+		return 255
+	}
+	return uint8(v + 0.5)
+}
+
+/*@}*/
@@ -0,0 +1,103 @@
+package zbar
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestParsePreprocess(t *testing.T) {
+	var good = map[string]Preprocess{
+		"equalize":       Equalize{},
+		"sauvola":        Sauvola{Window: 15, K: 0.34, R: 128},
+		"sauvola=31,0.2": Sauvola{Window: 31, K: 0.2, R: 128},
+		"unsharp=3,1.5":  Unsharp{Radius: 3, Amount: 1.5},
+		"median=5":       Median{Size: 5},
+		"gamma=0.5":      Gamma{Gamma: 0.5},
+		"grayscale=min":  Grayscale{Channel: "min"},
+		"Grayscale=R":    Grayscale{Channel: "r"},
+		"MEDIAN=3":       Median{Size: 3},
+	}
+	for spec, want := range good {
+		p, err := ParsePreprocess(spec)
+		if err != nil {
+			t.Errorf("%s: %v", spec, err)
+		} else if p != want {
+			t.Errorf("%s: got %#v, want %#v", spec, p, want)
+		}
+	}
+
+	for _, spec := range []string{"blur", "gamma", "gamma=x", "median=4", "sauvola=1", "grayscale=cyan"} {
+		if _, err := ParsePreprocess(spec); err == nil {
+			t.Errorf("%s: expected error", spec)
+		}
+	}
+}
+
+func TestPreprocessStages(t *testing.T) {
+	// dim, low contrast image: bars of 100 on background of 110
+	var src = image.NewGray(image.Rect(0, 0, 20, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 20; x++ {
+			var v uint8 = 110
+			if x%4 < 2 {
+				v = 100
+			}
+			src.SetGray(x, y, color.Gray{Y: v})
+		}
+	}
+	var orig = append([]uint8(nil), src.Pix...)
+
+	var eq = Equalize{}.Apply(src)
+	if eq.GrayAt(0, 0).Y != 0 || eq.GrayAt(2, 0).Y != 255 {
+		t.Errorf("equalize: %d %d", eq.GrayAt(0, 0).Y, eq.GrayAt(2, 0).Y)
+	}
+
+	var bin = Sauvola{Window: 7, K: 0.05, R: 128}.Apply(src)
+	if bin.GrayAt(8, 8).Y != 0 || bin.GrayAt(10, 8).Y != 255 {
+		t.Errorf("sauvola: %d %d", bin.GrayAt(8, 8).Y, bin.GrayAt(10, 8).Y)
+	}
+
+	if g := (Gamma{Gamma: 1}).Apply(src); g.GrayAt(3, 3).Y != 110 {
+		t.Errorf("gamma 1: %d", g.GrayAt(3, 3).Y)
+	}
+
+	var noisy = image.NewGray(image.Rect(0, 0, 5, 5))
+	noisy.SetGray(2, 2, color.Gray{Y: 255})
+	if m := (Median{Size: 3}).Apply(noisy); m.GrayAt(2, 2).Y != 0 {
+		t.Errorf("median: %d", m.GrayAt(2, 2).Y)
+	}
+
+	var sharp = Pipeline{Unsharp{Radius: 1, Amount: 2}, Gamma{Gamma: 1}}.Apply(src)
+	if sharp.GrayAt(1, 5).Y >= 100 {
+		t.Errorf("unsharp: edge not enhanced: %d", sharp.GrayAt(1, 5).Y)
+	}
+
+	for i := range orig {
+		if src.Pix[i] != orig[i] {
+			t.Fatal("source image modified")
+		}
+	}
+}
+
+func TestGrayscaleChannel(t *testing.T) {
+	var img = image.NewRGBA(image.Rect(0, 0, 1, 1))
+	img.Set(0, 0, color.RGBA{R: 200, G: 50, B: 120, A: 255})
+
+	for channel, want := range map[string]uint8{"r": 200, "g": 50, "B": 120, "min": 50, "Max": 200} {
+		if got := (Grayscale{Channel: channel}).Convert(img).GrayAt(0, 0).Y; got != want {
+			t.Errorf("%s: %d, want %d", channel, got, want)
+		}
+	}
+}
+
+func TestPreprocessEmptyImage(t *testing.T) {
+	for _, r := range []image.Rectangle{image.Rect(0, 0, 0, 5), image.Rect(0, 0, 5, 0)} {
+		var src = image.NewGray(r)
+		for _, p := range []Preprocess{Unsharp{Radius: 2, Amount: 1}, Sauvola{Window: 15, K: 0.34, R: 128}} {
+			if dst := p.Apply(src); dst.Bounds() != r {
+				t.Errorf("%T %v: bounds %v", p, r, dst.Bounds())
+			}
+		}
+	}
+}
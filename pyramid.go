@@ -0,0 +1,132 @@
+package zbar
+
+import (
+	"image"
+	"math"
+	"time"
+)
+
+/*------------------------------------------------------------*/
+/** @name Multi-scale scanning
+ * small codes in very large photos have too few pixels per module
+ * after the scanner's own sampling, while codes filling a close-up
+ * frame can be too large for it.  when a profile lists scales, the
+ * image is additionally scanned at each scale and the results of all
+ * levels are merged
+ */
+/*@{*/
+
+/** scan the source resolution and then every configured scale.
+ * levels are scanned in the configured order until the time budget
+ * (if any) is used up; the source resolution is always scanned.
+ * symbols found on several levels are reported once, with the
+ * location from the first level they were found on.
+ */
+func (s *Scanner) scanPyramid(gray *image.Gray, sequence uint32) ([]Symbol, error) {
+	var start = time.Now()
+	var budget = time.Duration(s.profile.ScaleBudgetMS) * time.Millisecond
+
+	symbols, err := s.scanRotated(gray, sequence)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, scale := range s.profile.Scales {
+		if budget > 0 && time.Since(start) >= budget {
+			break
+		}
+		if scale <= 0 || scale == 1 {
+			continue
+		}
+
+		var level = scaleGray(gray, scale)
+		if level.Rect.Dx() < 8 || level.Rect.Dy() < 8 {
+			continue
+		}
+		found, err := s.scanRotated(level, sequence)
+		if err != nil {
+			return nil, err
+		}
+
+		var min = gray.Bounds().Min
+		for _, sym := range found {
+			for i, pt := range sym.Points {
+				sym.Points[i] = image.Pt(
+					int(math.Floor((float64(pt.X)+0.5)/scale)),
+					int(math.Floor((float64(pt.Y)+0.5)/scale)),
+				).Add(min)
+			}
+			symbols = mergeSymbol(symbols, sym)
+		}
+	}
+
+	return symbols, nil
+}
+
+/** append sym unless an equal symbol at the same place is already
+ * present.  symbols are equal if type and data match, and at the same
+ * place if their bounding boxes (grown by a small margin, as linear
+ * codes report only their scan line locations) overlap.
+ */
+func mergeSymbol(symbols []Symbol, sym Symbol) []Symbol {
+	var b = grow(sym.Bounds(), 8)
+	for _, other := range symbols {
+		if other.Type == sym.Type && other.Data == sym.Data && grow(other.Bounds(), 8).Overlaps(b) {
+			return symbols
+		}
+	}
+	return append(symbols, sym)
+}
+
+func grow(r image.Rectangle, n int) image.Rectangle {
+	return image.Rect(r.Min.X-n, r.Min.Y-n, r.Max.X+n, r.Max.Y+n)
+}
+
+/** resample an image by factor.
+ * downscaling averages the source pixels covered by each destination
+ * pixel, upscaling interpolates bilinearly.
+ * @returns the scaled image with bounds starting at (0,0)
+ */
+func scaleGray(src *image.Gray, factor float64) *image.Gray {
+	var sb = src.Bounds()
+	var dw = int(math.Round(float64(sb.Dx()) * factor))
+	var dh = int(math.Round(float64(sb.Dy()) * factor))
+	var dst = image.NewGray(image.Rect(0, 0, dw, dh))
+	if dw == 0 || dh == 0 {
+		return dst
+	}
+
+	if factor > 1 {
+		for y := 0; y < dh; y++ {
+			var fy = (float64(y)+0.5)/factor - 0.5 + float64(sb.Min.Y)
+			for x := 0; x < dw; x++ {
+				var fx = (float64(x)+0.5)/factor - 0.5 + float64(sb.Min.X)
+				// clamp so borders repeat instead of fading to white
+				fx = math.Max(float64(sb.Min.X), math.Min(fx, float64(sb.Max.X-1)))
+				fy = math.Max(float64(sb.Min.Y), math.Min(fy, float64(sb.Max.Y-1)))
+				dst.Pix[y*dst.Stride+x] = bilinear(src, fx, fy)
+			}
+		}
+		return dst
+	}
+
+	for y := 0; y < dh; y++ {
+		var y0 = y * sb.Dy() / dh
+		var y1 = max((y+1)*sb.Dy()/dh, y0+1)
+		for x := 0; x < dw; x++ {
+			var x0 = x * sb.Dx() / dw
+			var x1 = max((x+1)*sb.Dx()/dw, x0+1)
+			var sum int
+			for sy := y0; sy < y1; sy++ {
+				var row = src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					sum += int(row[sx])
+				}
+			}
+			dst.Pix[y*dst.Stride+x] = uint8(sum / ((y1 - y0) * (x1 - x0)))
+		}
+	}
+	return dst
+}
+
+/*@}*/
@@ -0,0 +1,45 @@
+package zbar
+
+import (
+	"image"
+	"testing"
+)
+
+func TestScaleGray(t *testing.T) {
+	var src = image.NewGray(image.Rect(0, 0, 4, 2))
+	copy(src.Pix, []uint8{0, 100, 200, 200, 100, 0, 200, 200})
+
+	var half = scaleGray(src, 0.5)
+	if b := half.Bounds(); b.Dx() != 2 || b.Dy() != 1 {
+		t.Fatalf("bounds = %v", b)
+	}
+	if half.Pix[0] != 50 || half.Pix[1] != 200 {
+		t.Errorf("pix = %v", half.Pix)
+	}
+
+	var double = scaleGray(src, 2)
+	if b := double.Bounds(); b.Dx() != 8 || b.Dy() != 4 {
+		t.Fatalf("bounds = %v", b)
+	}
+	if double.Pix[0] != 0 || double.Pix[7] != 200 {
+		t.Errorf("corners = %d %d", double.Pix[0], double.Pix[7])
+	}
+}
+
+func TestMergeSymbol(t *testing.T) {
+	var a = Symbol{Type: ZBAR_QRCODE, Data: "x", Points: []image.Point{{10, 10}, {40, 40}}}
+	var near = Symbol{Type: ZBAR_QRCODE, Data: "x", Points: []image.Point{{12, 11}, {41, 39}}}
+	var far = Symbol{Type: ZBAR_QRCODE, Data: "x", Points: []image.Point{{200, 200}, {240, 240}}}
+	var other = Symbol{Type: ZBAR_CODE128, Data: "x", Points: near.Points}
+
+	var merged = mergeSymbol(nil, a)
+	merged = mergeSymbol(merged, near)
+	merged = mergeSymbol(merged, far)
+	merged = mergeSymbol(merged, other)
+	if len(merged) != 3 {
+		t.Fatalf("merged %d symbols, want 3", len(merged))
+	}
+	if b := a.Bounds(); b != image.Rect(10, 10, 41, 41) {
+		t.Errorf("bounds = %v", b)
+	}
+}
@@ -0,0 +1,23 @@
+package zbar
+
+import (
+	"fmt"
+
+	"github.com/zooyer/zbar/qrcontent"
+)
+
+/*------------------------------------------------------------*/
+/** @name QR code payloads
+ * see package qrcontent
+ */
+/*@{*/
+
+/** classify and parse the payload of a QR code symbol. */
+func (sym Symbol) Content() (qrcontent.Content, error) {
+	if sym.Type&ZBAR_SYMBOL != ZBAR_QRCODE {
+		return nil, fmt.Errorf("zbar: %v is not a QR code symbol", sym.Type)
+	}
+	return qrcontent.Parse(sym.Data)
+}
+
+/*@}*/
@@ -0,0 +1,212 @@
+package qrcontent
+
+import (
+	"strings"
+)
+
+/** contact card from a MECARD or vCard payload. */
+type Contact struct {
+	Name     string // formatted name
+	Org      string
+	Title    string
+	Phones   []string
+	Emails   []string
+	URLs     []string
+	Address  string
+	Note     string
+	Birthday string // as given, usually YYYYMMDD or YYYY-MM-DD
+}
+
+func (*Contact) Kind() Kind { return KindContact }
+
+/** parse a MECARD or vCard payload. */
+func ParseContact(data string) (*Contact, error) {
+	if hasPrefixFold(data, "mecard:") {
+		return parseMECARD(data[len("mecard:"):])
+	}
+	if hasPrefixFold(data, "begin:vcard") {
+		return parseVCard(data)
+	}
+	return nil, &Error{KindContact, "", "missing MECARD: or BEGIN:VCARD prefix"}
+}
+
+/** MECARD:N:Doe,John;TEL:...;EMAIL:...;; */
+func parseMECARD(body string) (*Contact, error) {
+	var c = &Contact{}
+	for _, f := range splitFields(body) {
+		switch f[0] {
+		case "N":
+			c.Name = mecardName(f[1])
+		case "ORG":
+			c.Org = f[1]
+		case "TITLE":
+			c.Title = f[1]
+		case "TEL", "TEL-AV":
+			c.Phones = append(c.Phones, f[1])
+		case "EMAIL":
+			c.Emails = append(c.Emails, f[1])
+		case "URL":
+			c.URLs = append(c.URLs, f[1])
+		case "ADR":
+			c.Address = f[1]
+		case "NOTE":
+			c.Note = f[1]
+		case "BDAY":
+			c.Birthday = f[1]
+		}
+	}
+	return c, validContact(c, "N")
+}
+
+/** "Last,First" to "First Last". */
+func mecardName(n string) string {
+	if last, first, ok := strings.Cut(n, ","); ok {
+		return strings.TrimSpace(strings.TrimSpace(first) + " " + strings.TrimSpace(last))
+	}
+	return n
+}
+
+/** vCard 2.1, 3.0 and 4.0. */
+func parseVCard(data string) (*Contact, error) {
+	var c = &Contact{}
+	var structured string
+	var ended bool
+	for _, line := range unfold(data) {
+		var name, _, value, ok = contentLine(line)
+		if !ok {
+			continue
+		}
+		switch name {
+		case "END":
+			ended = strings.EqualFold(value, "VCARD")
+		case "FN":
+			c.Name = unescapeText(value)
+		case "N":
+			structured = value
+		case "ORG":
+			c.Org = strings.Join(nonEmpty(splitText(value, ';')), ", ")
+		case "TITLE":
+			c.Title = unescapeText(value)
+		case "TEL":
+			c.Phones = append(c.Phones, strings.TrimPrefix(value, "tel:"))
+		case "EMAIL":
+			c.Emails = append(c.Emails, value)
+		case "URL":
+			c.URLs = append(c.URLs, value)
+		case "ADR":
+			c.Address = strings.Join(nonEmpty(splitText(value, ';')), ", ")
+		case "NOTE":
+			c.Note = unescapeText(value)
+		case "BDAY":
+			c.Birthday = value
+		}
+	}
+	if !ended {
+		return nil, &Error{KindContact, "END", "missing END:VCARD"}
+	}
+	if c.Name == "" && structured != "" {
+		// N:Family;Given;Additional;Prefix;Suffix
+		var parts = splitText(structured, ';')
+		for len(parts) < 5 {
+			parts = append(parts, "")
+		}
+		c.Name = strings.Join(nonEmpty([]string{parts[3], parts[1], parts[2], parts[0], parts[4]}), " ")
+	}
+	return c, validContact(c, "FN")
+}
+
+func validContact(c *Contact, nameField string) error {
+	if c.Name == "" {
+		return &Error{KindContact, nameField, "missing name"}
+	}
+	for _, email := range c.Emails {
+		if !validAddress(email) {
+			return &Error{KindContact, "EMAIL", "invalid address " + email}
+		}
+	}
+	for _, tel := range c.Phones {
+		if !validPhone(tel) {
+			return &Error{KindContact, "TEL", "invalid number " + tel}
+		}
+	}
+	return nil
+}
+
+/** split a vCard/iCalendar payload into unfolded content lines. */
+func unfold(data string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, "\r"))
+	}
+	return lines
+}
+
+/** split "NAME;PARAM=x:value" into upper cased name, parameters and
+ * value.  group prefixes ("item1.TEL") are dropped.
+ */
+func contentLine(line string) (string, map[string]string, string, bool) {
+	var head, value, ok = strings.Cut(line, ":")
+	if !ok {
+		return "", nil, "", false
+	}
+	var parts = strings.Split(head, ";")
+	var name = strings.ToUpper(parts[0])
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	var params = make(map[string]string)
+	for _, p := range parts[1:] {
+		var k, v, _ = strings.Cut(p, "=")
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return name, params, value, true
+}
+
+/** split a text value at unescaped sep and unescape the parts. */
+func splitText(value string, sep byte) []string {
+	var parts []string
+	var start = 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, unescapeText(value[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, unescapeText(value[start:]))
+}
+
+/** RFC 6350/5545 text unescaping. */
+func unescapeText(s string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			if s[i] == 'n' || s[i] == 'N' {
+				b.WriteByte('\n')
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+func nonEmpty(parts []string) []string {
+	var out []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
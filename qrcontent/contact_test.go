@@ -0,0 +1,63 @@
+package qrcontent
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMECARD(t *testing.T) {
+	c, err := ParseContact(`MECARD:N:Doe,John;TEL:+1 555 0100;EMAIL:john@example.com;ADR:1 Main St\, Springfield;BDAY:19800131;;`)
+	var want = &Contact{
+		Name:     "John Doe",
+		Phones:   []string{"+1 555 0100"},
+		Emails:   []string{"john@example.com"},
+		Address:  "1 Main St, Springfield",
+		Birthday: "19800131",
+	}
+	if err != nil || !reflect.DeepEqual(c, want) {
+		t.Errorf("got %+v %v", c, err)
+	}
+
+	if _, err = ParseContact("MECARD:TEL:123;;"); err == nil {
+		t.Error("missing name accepted")
+	}
+	if _, err = ParseContact("MECARD:N:x;EMAIL:not an address;;"); err == nil {
+		t.Error("invalid email accepted")
+	}
+}
+
+func TestParseVCard(t *testing.T) {
+	var card = "BEGIN:VCARD\r\n" +
+		"VERSION:3.0\r\n" +
+		"N:Gump;Forrest;;Mr.;\r\n" +
+		"ORG:Bubba Gump Shrimp Co.\r\n" +
+		"TITLE:Shrimp Man\r\n" +
+		"TEL;TYPE=WORK,VOICE:(111) 555-1212\r\n" +
+		"item1.EMAIL;TYPE=INTERNET:forrest@example.com\r\n" +
+		"ADR;TYPE=HOME:;;42 Plantation St.;Baytown;LA;30314;United States of America\r\n" +
+		"NOTE:line one\\nline\r\n" +
+		"  two\r\n" +
+		"END:VCARD\r\n"
+	c, err := ParseContact(card)
+	var want = &Contact{
+		Name:    "Mr. Forrest Gump",
+		Org:     "Bubba Gump Shrimp Co.",
+		Title:   "Shrimp Man",
+		Phones:  []string{"(111) 555-1212"},
+		Emails:  []string{"forrest@example.com"},
+		Address: "42 Plantation St., Baytown, LA, 30314, United States of America",
+		Note:    "line one\nline two",
+	}
+	if err != nil || !reflect.DeepEqual(c, want) {
+		t.Errorf("got %+v %v", c, err)
+	}
+
+	c, err = ParseContact("BEGIN:VCARD\nVERSION:4.0\nFN:Jane Roe\nTEL;VALUE=uri:tel:+1-555-0199\nEND:VCARD")
+	if err != nil || c.Name != "Jane Roe" || c.Phones[0] != "+1-555-0199" {
+		t.Errorf("4.0: %+v %v", c, err)
+	}
+
+	if _, err = ParseContact("BEGIN:VCARD\nFN:x\n"); err == nil {
+		t.Error("unterminated card accepted")
+	}
+}
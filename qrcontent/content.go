@@ -0,0 +1,224 @@
+/** Package qrcontent classifies and parses common QR code payloads.
+ *
+ * QR codes carry plain text, but a handful of conventions are used
+ * almost universally: web URLs, WIFI: network configurations,
+ * MECARD and vCard contacts, mailto:, tel:, sms: and geo: URIs,
+ * iCalendar events and otpauth:// one-time password seeds.  Parse()
+ * recognizes each by its prefix and returns a typed, validated value.
+ */
+package qrcontent
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+/** payload type. */
+type Kind int
+
+const (
+	KindText    Kind = iota /**< anything else */
+	KindURL                 /**< http(s) URL */
+	KindWiFi                /**< WIFI: network configuration */
+	KindContact             /**< MECARD or vCard */
+	KindEmail               /**< mailto: URI or MATMSG */
+	KindPhone               /**< tel: URI */
+	KindSMS                 /**< sms: URI or SMSTO */
+	KindGeo                 /**< geo: URI */
+	KindEvent               /**< iCalendar VEVENT */
+	KindOTP                 /**< otpauth:// URI */
+)
+
+var kindNames = [...]string{"text", "url", "wifi", "contact", "email", "phone", "sms", "geo", "event", "otp"}
+
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return "unknown"
+	}
+	return kindNames[k]
+}
+
+/** parsed payload, one of *Text, *URL, *WiFi, *Contact, *Email,
+ * *Phone, *SMS, *Geo, *Event and *OTP.
+ */
+type Content interface {
+	Kind() Kind
+}
+
+/** invalid payload. */
+type Error struct {
+	Kind  Kind
+	Field string // offending field, if any
+	Msg   string
+}
+
+func (e *Error) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("qrcontent: %v: %s", e.Kind, e.Msg)
+	}
+	return fmt.Sprintf("qrcontent: %v: %s: %s", e.Kind, e.Field, e.Msg)
+}
+
+/** payload prefixes, matched case-insensitively. */
+var prefixes = []struct {
+	prefix string
+	kind   Kind
+}{
+	{"http://", KindURL},
+	{"https://", KindURL},
+	{"urlto:", KindURL},
+	{"wifi:", KindWiFi},
+	{"mecard:", KindContact},
+	{"begin:vcard", KindContact},
+	{"mailto:", KindEmail},
+	{"matmsg:", KindEmail},
+	{"tel:", KindPhone},
+	{"sms:", KindSMS},
+	{"smsto:", KindSMS},
+	{"geo:", KindGeo},
+	{"begin:vcalendar", KindEvent},
+	{"begin:vevent", KindEvent},
+	{"otpauth://", KindOTP},
+}
+
+/** classify a payload by its prefix. */
+func Classify(data string) Kind {
+	var lower = strings.ToLower(strings.TrimSpace(data))
+	for _, p := range prefixes {
+		if strings.HasPrefix(lower, p.prefix) {
+			return p.kind
+		}
+	}
+	return KindText
+}
+
+/** classify and parse a payload.
+ * payloads that look like one of the supported types but fail to
+ * parse return an *Error.
+ */
+func Parse(data string) (Content, error) {
+	var trimmed = strings.TrimSpace(data)
+	switch Classify(trimmed) {
+	case KindURL:
+		return ParseURL(trimmed)
+	case KindWiFi:
+		return ParseWiFi(trimmed)
+	case KindContact:
+		return ParseContact(trimmed)
+	case KindEmail:
+		return ParseEmail(trimmed)
+	case KindPhone:
+		return ParsePhone(trimmed)
+	case KindSMS:
+		return ParseSMS(trimmed)
+	case KindGeo:
+		return ParseGeo(trimmed)
+	case KindEvent:
+		return ParseEvent(trimmed)
+	case KindOTP:
+		return ParseOTP(trimmed)
+	}
+	return &Text{Text: data}, nil
+}
+
+/** plain text. */
+type Text struct {
+	Text string
+}
+
+func (*Text) Kind() Kind { return KindText }
+
+/** web URL. */
+type URL struct {
+	URL *url.URL
+}
+
+func (*URL) Kind() Kind { return KindURL }
+
+/** parse an http(s) URL, also in the URLTO: form. */
+func ParseURL(data string) (*URL, error) {
+	if hasPrefixFold(data, "urlto:") {
+		data = data[len("urlto:"):]
+		if !strings.Contains(data, "://") {
+			data = "http://" + data
+		}
+	}
+	u, err := url.Parse(data)
+	if err != nil {
+		return nil, &Error{KindURL, "", err.Error()}
+	}
+	if u.Scheme = strings.ToLower(u.Scheme); u.Scheme != "http" && u.Scheme != "https" {
+		return nil, &Error{KindURL, "scheme", "not http or https"}
+	}
+	if u.Host == "" {
+		return nil, &Error{KindURL, "host", "missing"}
+	}
+	return &URL{URL: u}, nil
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+/** split the "KEY:value;KEY:value;;" body of WIFI:, MECARD: and
+ * MATMSG: payloads.  backslash escapes the next character.
+ * @returns the fields in order, keys upper cased
+ */
+func splitFields(body string) [][2]string {
+	var fields [][2]string
+	var field strings.Builder
+	var flush = func() {
+		var f = field.String()
+		field.Reset()
+		if f == "" {
+			return
+		}
+		var key, value, _ = cutUnescaped(f)
+		fields = append(fields, [2]string{strings.ToUpper(key), value})
+	}
+
+	for i := 0; i < len(body); i++ {
+		switch c := body[i]; {
+		case c == '\\' && i+1 < len(body):
+			field.WriteByte(c)
+			field.WriteByte(body[i+1])
+			i++
+		case c == ';':
+			flush()
+		default:
+			field.WriteByte(c)
+		}
+	}
+	flush()
+	return fields
+}
+
+/** split an escaped field at its first unescaped ':' and unescape
+ * the value.
+ */
+func cutUnescaped(f string) (string, string, bool) {
+	for i := 0; i < len(f); i++ {
+		switch f[i] {
+		case '\\':
+			i++
+		case ':':
+			return f[:i], unescape(f[i+1:]), true
+		}
+	}
+	return "", unescape(f), false
+}
+
+func unescape(s string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
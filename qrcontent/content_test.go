@@ -0,0 +1,61 @@
+package qrcontent
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	for data, want := range map[string]Kind{
+		"hello":                      KindText,
+		"HTTPS://example.com":        KindURL,
+		"URLTO:example.com":          KindURL,
+		"WIFI:S:x;;":                 KindWiFi,
+		"MECARD:N:Doe,John;;":        KindContact,
+		"BEGIN:VCARD\nEND:VCARD":     KindContact,
+		"mailto:a@example.com":       KindEmail,
+		"tel:+41441234567":           KindPhone,
+		"SMSTO:123:hi":               KindSMS,
+		"geo:1,2":                    KindGeo,
+		"BEGIN:VEVENT\nEND:VEVENT":   KindEvent,
+		"otpauth://totp/a?secret=AA": KindOTP,
+		"  http://example.com/  \n":  KindURL,
+		"wifi-is-not-a-prefix":       KindText,
+	} {
+		if got := Classify(data); got != want {
+			t.Errorf("%q: %v, want %v", data, got, want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	c, err := Parse("just some text")
+	if text, ok := c.(*Text); err != nil || !ok || text.Text != "just some text" {
+		t.Errorf("text: %#v %v", c, err)
+	}
+
+	c, err = Parse("https://example.com/a?b=c")
+	if u, ok := c.(*URL); err != nil || !ok || u.URL.Host != "example.com" || u.URL.RawQuery != "b=c" {
+		t.Errorf("url: %#v %v", c, err)
+	}
+	c, err = Parse("URLTO:www.example.com")
+	if u, ok := c.(*URL); err != nil || !ok || u.URL.String() != "http://www.example.com" {
+		t.Errorf("urlto: %#v %v", c, err)
+	}
+
+	_, err = Parse("http://")
+	var perr *Error
+	if !errors.As(err, &perr) || perr.Kind != KindURL || perr.Field != "host" {
+		t.Errorf("empty host: %v", err)
+	}
+	if err.Error() != "qrcontent: url: host: missing" {
+		t.Errorf("message %q", err)
+	}
+}
+
+func TestSplitFields(t *testing.T) {
+	var fields = splitFields(`S:a\;b\:c;P:x\\y;;`)
+	if len(fields) != 2 || fields[0] != [2]string{"S", "a;b:c"} || fields[1] != [2]string{"P", `x\y`} {
+		t.Errorf("fields = %q", fields)
+	}
+}
@@ -0,0 +1,162 @@
+package qrcontent
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+/** calendar event from an iCalendar VEVENT.
+ * times in UTC or with a known TZID are absolute; floating times are
+ * returned in time.Local.  all-day events have midnight times.
+ */
+type Event struct {
+	Summary     string
+	Description string
+	Location    string
+	Start, End  time.Time
+	AllDay      bool
+	UID         string
+}
+
+func (*Event) Kind() Kind { return KindEvent }
+
+/** parse the first VEVENT of an iCalendar payload. */
+func ParseEvent(data string) (*Event, error) {
+	var e = &Event{}
+	var in, done bool
+	var hasEnd bool
+	var duration time.Duration
+	for _, line := range unfold(data) {
+		var name, params, value, ok = contentLine(line)
+		if !ok {
+			continue
+		}
+		if name == "BEGIN" && strings.EqualFold(value, "VEVENT") {
+			in = true
+			continue
+		}
+		if !in {
+			continue
+		}
+		var err error
+		switch name {
+		case "END":
+			if strings.EqualFold(value, "VEVENT") {
+				done = true
+			}
+		case "SUMMARY":
+			e.Summary = unescapeText(value)
+		case "DESCRIPTION":
+			e.Description = unescapeText(value)
+		case "LOCATION":
+			e.Location = unescapeText(value)
+		case "UID":
+			e.UID = value
+		case "DTSTART":
+			e.Start, e.AllDay, err = parseDateTime(value, params)
+			if err != nil {
+				return nil, &Error{KindEvent, name, err.Error()}
+			}
+		case "DTEND":
+			e.End, _, err = parseDateTime(value, params)
+			if err != nil {
+				return nil, &Error{KindEvent, name, err.Error()}
+			}
+			hasEnd = true
+		case "DURATION":
+			if duration, err = parseDuration(value); err != nil {
+				return nil, &Error{KindEvent, name, err.Error()}
+			}
+		}
+		if done {
+			break
+		}
+	}
+
+	switch {
+	case !in:
+		return nil, &Error{KindEvent, "", "missing BEGIN:VEVENT"}
+	case !done:
+		return nil, &Error{KindEvent, "", "missing END:VEVENT"}
+	case e.Start.IsZero():
+		return nil, &Error{KindEvent, "DTSTART", "missing start"}
+	}
+	if !hasEnd {
+		e.End = e.Start.Add(duration)
+		if e.AllDay && duration == 0 {
+			e.End = e.Start.AddDate(0, 0, 1)
+		}
+	}
+	if e.End.Before(e.Start) {
+		return nil, &Error{KindEvent, "DTEND", "end before start"}
+	}
+	return e, nil
+}
+
+/** DATE or DATE-TIME value with optional TZID parameter. */
+func parseDateTime(value string, params map[string]string) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == 8 {
+		t, err := time.ParseInLocation("20060102", value, time.Local)
+		if err != nil {
+			return time.Time{}, false, errors.New("invalid date " + value)
+		}
+		return t, true, nil
+	}
+
+	var loc = time.Local
+	if strings.HasSuffix(value, "Z") {
+		value, loc = value[:len(value)-1], time.UTC
+	} else if tzid := params["TZID"]; tzid != "" {
+		l, err := time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, false, errors.New("unknown time zone " + tzid)
+		}
+		loc = l
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	if err != nil {
+		return time.Time{}, false, errors.New("invalid date-time " + value)
+	}
+	return t, false, nil
+}
+
+/** RFC 5545 duration, eg "PT1H30M" or "P1D". */
+func parseDuration(value string) (time.Duration, error) {
+	var s = strings.TrimPrefix(strings.TrimPrefix(value, "+"), "P")
+	if len(s) == len(value) || s == "" {
+		return 0, errors.New("invalid duration " + value)
+	}
+	var d time.Duration
+	var n int64
+	var digits, inTime, seen bool
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			n, digits = n*10+int64(c-'0'), true
+			continue
+		case c == 'T' && !inTime && !digits:
+			inTime = true
+			continue
+		case !digits:
+			return 0, errors.New("invalid duration " + value)
+		case c == 'W' && !inTime:
+			d += time.Duration(n) * 7 * 24 * time.Hour
+		case c == 'D' && !inTime:
+			d += time.Duration(n) * 24 * time.Hour
+		case c == 'H' && inTime:
+			d += time.Duration(n) * time.Hour
+		case c == 'M' && inTime:
+			d += time.Duration(n) * time.Minute
+		case c == 'S' && inTime:
+			d += time.Duration(n) * time.Second
+		default:
+			return 0, errors.New("invalid duration " + value)
+		}
+		n, digits, seen = 0, false, true
+	}
+	if digits || !seen {
+		return 0, errors.New("invalid duration " + value)
+	}
+	return d, nil
+}
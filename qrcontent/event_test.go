@@ -0,0 +1,66 @@
+package qrcontent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseEvent(t *testing.T) {
+	var cal = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:42@example.com\r\n" +
+		"SUMMARY:Team meeting\\, weekly\r\n" +
+		"LOCATION:Room 1\r\n" +
+		"DTSTART:20250310T090000Z\r\n" +
+		"DURATION:PT1H30M\r\n" +
+		"END:VEVENT\r\nEND:VCALENDAR\r\n"
+	e, err := ParseEvent(cal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var start = time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	if e.Summary != "Team meeting, weekly" || e.Location != "Room 1" || e.UID != "42@example.com" ||
+		!e.Start.Equal(start) || !e.End.Equal(start.Add(90*time.Minute)) || e.AllDay {
+		t.Errorf("got %+v", e)
+	}
+
+	e, err = ParseEvent("BEGIN:VEVENT\nSUMMARY:Holiday\nDTSTART;VALUE=DATE:20251225\nEND:VEVENT")
+	if err != nil || !e.AllDay || e.End.Sub(e.Start) != 24*time.Hour {
+		t.Errorf("all day: %+v %v", e, err)
+	}
+
+	e, err = ParseEvent("BEGIN:VEVENT\nDTSTART;TZID=Europe/Zurich:20250701T120000\nDTEND;TZID=Europe/Zurich:20250701T130000\nEND:VEVENT")
+	if err == nil && !e.Start.Equal(time.Date(2025, 7, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("tzid: %v", e.Start)
+	}
+
+	for data, field := range map[string]string{
+		"BEGIN:VEVENT\nSUMMARY:x\nEND:VEVENT":                                        "DTSTART",
+		"BEGIN:VEVENT\nDTSTART:2025-01-01\nEND:VEVENT":                               "DTSTART",
+		"BEGIN:VEVENT\nDTSTART:20250101T100000Z\nDTEND:20250101T090000Z\nEND:VEVENT": "DTEND",
+		"BEGIN:VEVENT\nDTSTART:20250101T100000Z\nDURATION:1H\nEND:VEVENT":            "DURATION",
+		"BEGIN:VEVENT\nDTSTART:20250101T100000Z\n":                                   "",
+	} {
+		_, err := ParseEvent(data)
+		if e, ok := err.(*Error); !ok || e.Field != field {
+			t.Errorf("%q: %v, want error on %q", data, err, field)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	for s, want := range map[string]time.Duration{
+		"PT15M":    15 * time.Minute,
+		"P1W":      7 * 24 * time.Hour,
+		"P1DT2H3S": 26*time.Hour + 3*time.Second,
+	} {
+		if d, err := parseDuration(s); err != nil || d != want {
+			t.Errorf("%s: %v %v", s, d, err)
+		}
+	}
+	for _, s := range []string{"P", "PT", "P1H", "PT1D", "P1"} {
+		if _, err := parseDuration(s); err == nil {
+			t.Errorf("%s accepted", s)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package qrcontent
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+/** one-time password seed from an otpauth:// URI, eg
+ * "otpauth://totp/Example:alice@example.com?secret=JBSWY3DPEHPK3PXP&issuer=Example".
+ */
+type OTP struct {
+	Type      string // "totp" or "hotp"
+	Issuer    string
+	Account   string
+	Secret    []byte // decoded key
+	Algorithm string // "SHA1", "SHA256" or "SHA512"
+	Digits    int
+	Period    int    // seconds, totp only
+	Counter   uint64 // initial counter, hotp only
+}
+
+func (*OTP) Kind() Kind { return KindOTP }
+
+/** parse an otpauth:// URI.
+ * missing parameters take the defaults SHA1, 6 digits and 30 seconds.
+ */
+func ParseOTP(data string) (*OTP, error) {
+	u, err := url.Parse(data)
+	if err != nil {
+		return nil, &Error{KindOTP, "", err.Error()}
+	}
+	if !strings.EqualFold(u.Scheme, "otpauth") {
+		return nil, &Error{KindOTP, "", "missing otpauth:// prefix"}
+	}
+
+	var o = &OTP{Type: strings.ToLower(u.Host), Algorithm: "SHA1", Digits: 6}
+	if o.Type != "totp" && o.Type != "hotp" {
+		return nil, &Error{KindOTP, "type", "not totp or hotp"}
+	}
+
+	var label = strings.TrimPrefix(u.Path, "/")
+	if issuer, account, ok := strings.Cut(label, ":"); ok {
+		o.Issuer, o.Account = strings.TrimSpace(issuer), strings.TrimSpace(account)
+	} else {
+		o.Account = label
+	}
+	if o.Account == "" {
+		return nil, &Error{KindOTP, "label", "missing account name"}
+	}
+
+	var q = u.Query()
+	if issuer := q.Get("issuer"); issuer != "" {
+		if o.Issuer != "" && o.Issuer != issuer {
+			return nil, &Error{KindOTP, "issuer", "does not match label prefix " + o.Issuer}
+		}
+		o.Issuer = issuer
+	}
+
+	var secret = strings.ToUpper(strings.TrimRight(strings.ReplaceAll(q.Get("secret"), " ", ""), "="))
+	if secret == "" {
+		return nil, &Error{KindOTP, "secret", "missing"}
+	}
+	if o.Secret, err = base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret); err != nil {
+		return nil, &Error{KindOTP, "secret", "invalid base32"}
+	}
+
+	if alg := q.Get("algorithm"); alg != "" {
+		switch o.Algorithm = strings.ToUpper(alg); o.Algorithm {
+		case "SHA1", "SHA256", "SHA512":
+		default:
+			return nil, &Error{KindOTP, "algorithm", "unsupported " + alg}
+		}
+	}
+	if digits := q.Get("digits"); digits != "" {
+		if o.Digits, err = strconv.Atoi(digits); err != nil || o.Digits < 6 || o.Digits > 8 {
+			return nil, &Error{KindOTP, "digits", "must be 6 to 8"}
+		}
+	}
+
+	switch o.Type {
+	case "totp":
+		o.Period = 30
+		if period := q.Get("period"); period != "" {
+			if o.Period, err = strconv.Atoi(period); err != nil || o.Period <= 0 {
+				return nil, &Error{KindOTP, "period", "must be a positive number of seconds"}
+			}
+		}
+	case "hotp":
+		var counter = q.Get("counter")
+		if counter == "" {
+			return nil, &Error{KindOTP, "counter", "missing"}
+		}
+		if o.Counter, err = strconv.ParseUint(counter, 10, 64); err != nil {
+			return nil, &Error{KindOTP, "counter", "not a number"}
+		}
+	}
+	return o, nil
+}
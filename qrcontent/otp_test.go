@@ -0,0 +1,36 @@
+package qrcontent
+
+import "testing"
+
+func TestParseOTP(t *testing.T) {
+	o, err := ParseOTP("otpauth://totp/ACME%20Co:john.doe@example.com?secret=HXDMVJECJJWSRB3HWIZR4IFUGFTMXBOZ&issuer=ACME%20Co&algorithm=SHA256&digits=8&period=60")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Type != "totp" || o.Issuer != "ACME Co" || o.Account != "john.doe@example.com" ||
+		o.Algorithm != "SHA256" || o.Digits != 8 || o.Period != 60 || len(o.Secret) != 20 {
+		t.Errorf("got %+v", o)
+	}
+
+	o, err = ParseOTP("otpauth://hotp/alice?secret=jbswy3dpehpk3pxp&counter=7")
+	if err != nil || o.Counter != 7 || o.Digits != 6 || o.Algorithm != "SHA1" || string(o.Secret) != "Hello!\xde\xad\xbe\xef" {
+		t.Errorf("hotp: %+v %v", o, err)
+	}
+
+	for data, field := range map[string]string{
+		"otpauth://motp/a?secret=AAAA":               "type",
+		"otpauth://totp/?secret=AAAA":                "label",
+		"otpauth://totp/a":                           "secret",
+		"otpauth://totp/a?secret=1111":               "secret",
+		"otpauth://totp/X:a?secret=AAAA&issuer=Y":    "issuer",
+		"otpauth://totp/a?secret=AAAA&algorithm=MD5": "algorithm",
+		"otpauth://totp/a?secret=AAAA&digits=4":      "digits",
+		"otpauth://totp/a?secret=AAAA&period=0":      "period",
+		"otpauth://hotp/a?secret=AAAA":               "counter",
+	} {
+		_, err := ParseOTP(data)
+		if e, ok := err.(*Error); !ok || e.Field != field {
+			t.Errorf("%s: %v, want error on %q", data, err, field)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package qrcontent
+
+import (
+	"math"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+/** e-mail draft from a mailto: URI or MATMSG payload. */
+type Email struct {
+	To      []string
+	CC      []string
+	BCC     []string
+	Subject string
+	Body    string
+}
+
+func (*Email) Kind() Kind { return KindEmail }
+
+/** telephone number from a tel: URI. */
+type Phone struct {
+	Number string
+}
+
+func (*Phone) Kind() Kind { return KindPhone }
+
+/** text message from an sms: URI or SMSTO payload. */
+type SMS struct {
+	Number string
+	Body   string
+}
+
+func (*SMS) Kind() Kind { return KindSMS }
+
+/** location from an RFC 5870 geo: URI. */
+type Geo struct {
+	Lat, Lon    float64
+	Alt         float64
+	HasAlt      bool
+	Uncertainty float64 // metres, 0 if not given
+	Query       string  // q= search term used by some generators
+}
+
+func (*Geo) Kind() Kind { return KindGeo }
+
+/** parse a mailto: URI or MATMSG:TO:..;SUB:..;BODY:..;; payload. */
+func ParseEmail(data string) (*Email, error) {
+	var e = &Email{}
+	switch {
+	case hasPrefixFold(data, "matmsg:"):
+		for _, f := range splitFields(data[len("matmsg:"):]) {
+			switch f[0] {
+			case "TO":
+				e.To = append(e.To, f[1])
+			case "SUB":
+				e.Subject = f[1]
+			case "BODY":
+				e.Body = f[1]
+			}
+		}
+	case hasPrefixFold(data, "mailto:"):
+		var to, query, _ = strings.Cut(data[len("mailto:"):], "?")
+		to, err := url.PathUnescape(to)
+		if err != nil {
+			return nil, &Error{KindEmail, "to", err.Error()}
+		}
+		e.To = splitAddresses(to)
+		values, err := parseHeaders(query)
+		if err != nil {
+			return nil, &Error{KindEmail, "", err.Error()}
+		}
+		for key, vals := range values {
+			switch strings.ToLower(key) {
+			case "to":
+				for _, v := range vals {
+					e.To = append(e.To, splitAddresses(v)...)
+				}
+			case "cc":
+				for _, v := range vals {
+					e.CC = append(e.CC, splitAddresses(v)...)
+				}
+			case "bcc":
+				for _, v := range vals {
+					e.BCC = append(e.BCC, splitAddresses(v)...)
+				}
+			case "subject":
+				e.Subject = vals[0]
+			case "body":
+				e.Body = vals[0]
+			}
+		}
+	default:
+		return nil, &Error{KindEmail, "", "missing mailto: or MATMSG: prefix"}
+	}
+
+	if len(e.To) == 0 {
+		return nil, &Error{KindEmail, "to", "missing recipient"}
+	}
+	for field, list := range map[string][]string{"to": e.To, "cc": e.CC, "bcc": e.BCC} {
+		for _, addr := range list {
+			if !validAddress(addr) {
+				return nil, &Error{KindEmail, field, "invalid address " + addr}
+			}
+		}
+	}
+	return e, nil
+}
+
+/** split the hfields of a mailto: or sms: URI.
+ * unlike url.ParseQuery '+' is kept: RFC 6068 and RFC 5724 values are
+ * only percent-encoded.
+ */
+func parseHeaders(query string) (url.Values, error) {
+	var values = url.Values{}
+	for _, field := range strings.Split(query, "&") {
+		if field == "" {
+			continue
+		}
+		var key, value, _ = strings.Cut(field, "=")
+		key, err := url.PathUnescape(key)
+		if err != nil {
+			return nil, err
+		}
+		if value, err = url.PathUnescape(value); err != nil {
+			return nil, err
+		}
+		values.Add(key, value)
+	}
+	return values, nil
+}
+
+func splitAddresses(s string) []string {
+	return nonEmpty(strings.Split(s, ","))
+}
+
+func validAddress(addr string) bool {
+	a, err := mail.ParseAddress(addr)
+	return err == nil && a.Name == "" && a.Address == addr
+}
+
+/** parse a tel: URI. */
+func ParsePhone(data string) (*Phone, error) {
+	if !hasPrefixFold(data, "tel:") {
+		return nil, &Error{KindPhone, "", "missing tel: prefix"}
+	}
+	var number, _, _ = strings.Cut(data[len("tel:"):], ";")
+	if !validPhone(number) {
+		return nil, &Error{KindPhone, "number", "invalid number " + number}
+	}
+	return &Phone{Number: number}, nil
+}
+
+/** digits with optional leading '+' and visual separators. */
+func validPhone(number string) bool {
+	var digits = 0
+	for i, c := range number {
+		switch {
+		case c >= '0' && c <= '9':
+			digits++
+		case c == '+' && i == 0:
+		case strings.ContainsRune(" -.()*#", c):
+		default:
+			return false
+		}
+	}
+	return digits > 0
+}
+
+/** parse an sms:number?body=text URI or SMSTO:number:text payload. */
+func ParseSMS(data string) (*SMS, error) {
+	var s = &SMS{}
+	switch {
+	case hasPrefixFold(data, "smsto:"):
+		s.Number, s.Body, _ = strings.Cut(data[len("smsto:"):], ":")
+	case hasPrefixFold(data, "sms:"):
+		var number, query, _ = strings.Cut(data[len("sms:"):], "?")
+		s.Number = number
+		values, err := parseHeaders(query)
+		if err != nil {
+			return nil, &Error{KindSMS, "", err.Error()}
+		}
+		s.Body = values.Get("body")
+	default:
+		return nil, &Error{KindSMS, "", "missing sms: or SMSTO: prefix"}
+	}
+	if !validPhone(s.Number) {
+		return nil, &Error{KindSMS, "number", "invalid number " + s.Number}
+	}
+	return s, nil
+}
+
+/** parse a geo:lat,lon[,alt][;u=uncertainty][?q=query] URI. */
+func ParseGeo(data string) (*Geo, error) {
+	if !hasPrefixFold(data, "geo:") {
+		return nil, &Error{KindGeo, "", "missing geo: prefix"}
+	}
+	var g = &Geo{}
+	var rest, query, _ = strings.Cut(data[len("geo:"):], "?")
+	if query != "" {
+		values, err := url.ParseQuery(query)
+		if err != nil {
+			return nil, &Error{KindGeo, "", err.Error()}
+		}
+		g.Query = values.Get("q")
+	}
+
+	var params = strings.Split(rest, ";")
+	var coords = strings.Split(params[0], ",")
+	if len(coords) < 2 || len(coords) > 3 {
+		return nil, &Error{KindGeo, "", "want lat,lon[,alt]"}
+	}
+	var err error
+	if g.Lat, err = parseFinite(coords[0]); err != nil || g.Lat < -90 || g.Lat > 90 {
+		return nil, &Error{KindGeo, "lat", "not a latitude"}
+	}
+	if g.Lon, err = parseFinite(coords[1]); err != nil || g.Lon < -180 || g.Lon > 180 {
+		return nil, &Error{KindGeo, "lon", "not a longitude"}
+	}
+	if len(coords) == 3 {
+		if g.Alt, err = parseFinite(coords[2]); err != nil {
+			return nil, &Error{KindGeo, "alt", "not a number"}
+		}
+		g.HasAlt = true
+	}
+	for _, p := range params[1:] {
+		var k, v, _ = strings.Cut(p, "=")
+		switch strings.ToLower(k) {
+		case "crs":
+			if !strings.EqualFold(v, "wgs84") {
+				return nil, &Error{KindGeo, "crs", "unsupported reference system " + v}
+			}
+		case "u":
+			if g.Uncertainty, err = parseFinite(v); err != nil || g.Uncertainty < 0 {
+				return nil, &Error{KindGeo, "u", "not an uncertainty"}
+			}
+		}
+	}
+	return g, nil
+}
+
+/** parse a decimal number, rejecting the NaN and Inf spellings
+ * strconv.ParseFloat accepts, which would slip past range checks.
+ */
+func parseFinite(s string) (float64, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err == nil && (math.IsNaN(f) || math.IsInf(f, 0)) {
+		return 0, strconv.ErrSyntax
+	}
+	return f, err
+}
@@ -0,0 +1,94 @@
+package qrcontent
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseEmail(t *testing.T) {
+	e, err := ParseEmail("mailto:a@example.com,b@example.com?subject=Hello%20there&cc=c@example.com&body=Hi")
+	var want = &Email{
+		To:      []string{"a@example.com", "b@example.com"},
+		CC:      []string{"c@example.com"},
+		Subject: "Hello there",
+		Body:    "Hi",
+	}
+	if err != nil || !reflect.DeepEqual(e, want) {
+		t.Errorf("mailto: %+v %v", e, err)
+	}
+
+	e, err = ParseEmail("mailto:foo@example.com?cc=bar+tag@example.com&body=1+1%3D2")
+	if err != nil || e.CC[0] != "bar+tag@example.com" || e.Body != "1+1=2" {
+		t.Errorf("plus: %+v %v", e, err)
+	}
+
+	e, err = ParseEmail("MATMSG:TO:a@example.com;SUB:Re\\: test;BODY:text;;")
+	if err != nil || e.To[0] != "a@example.com" || e.Subject != "Re: test" || e.Body != "text" {
+		t.Errorf("matmsg: %+v %v", e, err)
+	}
+
+	for _, data := range []string{"mailto:", "mailto:nobody", "mailto:a@example.com?bcc=bad"} {
+		if _, err := ParseEmail(data); err == nil {
+			t.Errorf("%s accepted", data)
+		}
+	}
+}
+
+func TestParsePhone(t *testing.T) {
+	p, err := ParsePhone("tel:+1-201-555-0123;ext=42")
+	if err != nil || p.Number != "+1-201-555-0123" {
+		t.Errorf("tel: %+v %v", p, err)
+	}
+	for _, data := range []string{"tel:", "tel:call me", "tel:1+2"} {
+		if _, err := ParsePhone(data); err == nil {
+			t.Errorf("%s accepted", data)
+		}
+	}
+}
+
+func TestParseSMS(t *testing.T) {
+	s, err := ParseSMS("sms:+15550100?body=see%20you")
+	if err != nil || s.Number != "+15550100" || s.Body != "see you" {
+		t.Errorf("sms: %+v %v", s, err)
+	}
+	s, err = ParseSMS("sms:+15551234?body=C++")
+	if err != nil || s.Body != "C++" {
+		t.Errorf("plus: %+v %v", s, err)
+	}
+	s, err = ParseSMS("SMSTO:+15550100:time: 5pm")
+	if err != nil || s.Number != "+15550100" || s.Body != "time: 5pm" {
+		t.Errorf("smsto: %+v %v", s, err)
+	}
+	if _, err = ParseSMS("sms:?body=x"); err == nil {
+		t.Error("missing number accepted")
+	}
+}
+
+func TestParseGeo(t *testing.T) {
+	g, err := ParseGeo("geo:47.3769,8.5417,408;crs=wgs84;u=25?q=Zurich")
+	var want = &Geo{Lat: 47.3769, Lon: 8.5417, Alt: 408, HasAlt: true, Uncertainty: 25, Query: "Zurich"}
+	if err != nil || !reflect.DeepEqual(g, want) {
+		t.Errorf("geo: %+v %v", g, err)
+	}
+
+	for data, field := range map[string]string{
+		"geo:91,0":          "lat",
+		"geo:0,181":         "lon",
+		"geo:0,0,high":      "alt",
+		"geo:0,0;crs=nad27": "crs",
+		"geo:0,0;u=-1":      "u",
+		"geo:0":             "",
+		"geo:NaN,0":         "lat",
+		"geo:0,nan":         "lon",
+		"geo:Inf,0":         "lat",
+		"geo:0,-Infinity":   "lon",
+		"geo:0,0,+Inf":      "alt",
+		"geo:0,0;u=NaN":     "u",
+		"geo:0,0;u=inf":     "u",
+	} {
+		_, err := ParseGeo(data)
+		if e, ok := err.(*Error); !ok || e.Field != field {
+			t.Errorf("%s: %v, want error on %q", data, err, field)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package qrcontent
+
+import (
+	"encoding/hex"
+	"strings"
+)
+
+/** WIFI: network configuration, eg
+ * "WIFI:T:WPA;S:home;P:secret password;;".
+ */
+type WiFi struct {
+	SSID     string
+	Security string // "WPA" (also WPA2/WPA3 personal), "SAE", "WEP", "WPA2-EAP" or "nopass"
+	Password string
+	Hidden   bool
+
+	// WPA2-EAP
+	EAP      string // method, eg "PEAP"
+	Identity string
+	Phase2   string
+}
+
+func (*WiFi) Kind() Kind { return KindWiFi }
+
+/** parse a WIFI: payload. */
+func ParseWiFi(data string) (*WiFi, error) {
+	if !hasPrefixFold(data, "wifi:") {
+		return nil, &Error{KindWiFi, "", "missing WIFI: prefix"}
+	}
+
+	var w = &WiFi{Security: "NOPASS"}
+	for _, f := range splitFields(data[len("wifi:"):]) {
+		switch f[0] {
+		case "S":
+			w.SSID = f[1]
+		case "T":
+			if f[1] != "" {
+				w.Security = strings.ToUpper(f[1])
+			}
+		case "P":
+			w.Password = f[1]
+		case "H":
+			w.Hidden = strings.EqualFold(f[1], "true")
+		case "E":
+			w.EAP = f[1]
+		case "I":
+			w.Identity = f[1]
+		case "PH2":
+			w.Phase2 = f[1]
+		}
+	}
+
+	if w.SSID == "" {
+		return nil, &Error{KindWiFi, "S", "missing SSID"}
+	}
+	switch w.Security {
+	case "NOPASS":
+		w.Security = "nopass"
+		if w.Password != "" {
+			return nil, &Error{KindWiFi, "P", "password given for open network"}
+		}
+	case "WEP":
+		if !validWEPKey(w.Password) {
+			return nil, &Error{KindWiFi, "P", "WEP key must be 5 or 13 characters or 10 or 26 hex digits"}
+		}
+	case "WPA", "WPA2", "WPA3", "SAE":
+		var n = len(w.Password)
+		if n < 8 || n > 64 || n == 64 && !isHex(w.Password) {
+			return nil, &Error{KindWiFi, "P", "WPA passphrase must be 8 to 63 characters or 64 hex digits"}
+		}
+	case "WPA2-EAP":
+		if w.EAP == "" {
+			return nil, &Error{KindWiFi, "E", "missing EAP method"}
+		}
+	default:
+		return nil, &Error{KindWiFi, "T", "unknown security type " + w.Security}
+	}
+	return w, nil
+}
+
+func validWEPKey(key string) bool {
+	switch len(key) {
+	case 5, 13:
+		return true
+	case 10, 26:
+		return isHex(key)
+	}
+	return false
+}
+
+func isHex(s string) bool {
+	_, err := hex.DecodeString(s)
+	return err == nil && len(s)%2 == 0
+}
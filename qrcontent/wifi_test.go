@@ -0,0 +1,38 @@
+package qrcontent
+
+import "testing"
+
+func TestParseWiFi(t *testing.T) {
+	w, err := ParseWiFi(`WIFI:T:WPA;S:my\;net;P:correct horse;H:true;;`)
+	if err != nil || w.SSID != "my;net" || w.Security != "WPA" || w.Password != "correct horse" || !w.Hidden {
+		t.Errorf("wpa: %+v %v", w, err)
+	}
+
+	w, err = ParseWiFi("WIFI:S:cafe;;")
+	if err != nil || w.Security != "nopass" {
+		t.Errorf("open: %+v %v", w, err)
+	}
+
+	w, err = ParseWiFi("WIFI:T:WPA2-EAP;S:corp;E:PEAP;PH2:MSCHAPV2;I:alice;P:pw;;")
+	if err != nil || w.EAP != "PEAP" || w.Phase2 != "MSCHAPV2" || w.Identity != "alice" {
+		t.Errorf("eap: %+v %v", w, err)
+	}
+
+	for data, field := range map[string]string{
+		"WIFI:T:WPA;P:secret123;;":      "S",
+		"WIFI:T:WPA;S:x;P:short;;":      "P",
+		"WIFI:T:WEP;S:x;P:123456;;":     "P",
+		"WIFI:T:WEP;S:x;P:zzzzzzzzzz;;": "P",
+		"WIFI:T:nopass;S:x;P:pw;;":      "P",
+		"WIFI:T:FOO;S:x;;":              "T",
+		"WIFI:T:WPA2-EAP;S:x;;":         "E",
+	} {
+		_, err := ParseWiFi(data)
+		if e, ok := err.(*Error); !ok || e.Field != field {
+			t.Errorf("%s: %v, want error on %s", data, err, field)
+		}
+	}
+	if _, err = ParseWiFi("WIFI:T:WEP;S:x;P:0123456789;;"); err != nil {
+		t.Errorf("hex WEP key: %v", err)
+	}
+}
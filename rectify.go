@@ -0,0 +1,158 @@
+package zbar
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+)
+
+/*------------------------------------------------------------*/
+/** @name Perspective correction
+ * the location of a QR code symbol is the quadrilateral of its outer
+ * corners, starting at the top left corner (the one with no finder
+ * pattern opposite) and continuing counter-clockwise: top left,
+ * bottom left, bottom right, top right.  mapping that quadrilateral
+ * onto a square with a homography yields an upright, deskewed crop of
+ * the symbol
+ */
+/*@{*/
+
+/** returned by Rectify for symbols without a four point location. */
+var ErrNotQuad = errors.New("zbar: symbol location is not a quadrilateral")
+
+/** returned for point sets that do not define a projective transform,
+ * eg when three of them are collinear.
+ */
+var ErrDegenerate = errors.New("zbar: degenerate quadrilateral")
+
+/** 3x3 projective transform in row-major order, normalized so the
+ * last element is 1.
+ */
+type Homography [9]float64
+
+/** compute the homography mapping each src point to the dst point at
+ * the same index.
+ */
+func NewHomography(src, dst [4][2]float64) (Homography, error) {
+	// 8 equations in h0..h7:
+	//   x' (h6 x + h7 y + 1) = h0 x + h1 y + h2
+	//   y' (h6 x + h7 y + 1) = h3 x + h4 y + h5
+	var a [8][9]float64
+	for i := 0; i < 4; i++ {
+		var x, y = src[i][0], src[i][1]
+		var u, v = dst[i][0], dst[i][1]
+		a[2*i] = [9]float64{x, y, 1, 0, 0, 0, -u * x, -u * y, u}
+		a[2*i+1] = [9]float64{0, 0, 0, x, y, 1, -v * x, -v * y, v}
+	}
+
+	// gaussian elimination with partial pivoting
+	for col := 0; col < 8; col++ {
+		var pivot = col
+		for row := col + 1; row < 8; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return Homography{}, ErrDegenerate
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		for row := 0; row < 8; row++ {
+			if row == col {
+				continue
+			}
+			var f = a[row][col] / a[col][col]
+			for k := col; k < 9; k++ {
+				a[row][k] -= f * a[col][k]
+			}
+		}
+	}
+
+	var h Homography
+	for i := 0; i < 8; i++ {
+		h[i] = a[i][8] / a[i][i]
+	}
+	h[8] = 1
+	return h, nil
+}
+
+/** map a point. */
+func (h Homography) Map(x, y float64) (float64, float64) {
+	var w = h[6]*x + h[7]*y + h[8]
+	return (h[0]*x + h[1]*y + h[2]) / w, (h[3]*x + h[4]*y + h[5]) / w
+}
+
+/** produce an upright, perspective corrected crop of a symbol.
+ * the symbol is mapped onto a size x size square, surrounded by a
+ * white border of margin pixels (eg as quiet zone for rescanning).
+ * samples are interpolated bilinearly; areas outside img are white.
+ * @param img the image the symbol was found in, in the coordinates of
+ * Symbol.Points
+ */
+func Rectify(img image.Image, sym Symbol, size, margin int) (image.Image, error) {
+	if len(sym.Points) != 4 {
+		return nil, ErrNotQuad
+	}
+	if size <= 0 || margin < 0 {
+		return nil, errors.New("zbar: invalid rectified size")
+	}
+
+	// corner points mark pixels; use their outer edges so a full
+	// symbol maps onto the full square
+	var quad [4][2]float64
+	var square = [4][2]float64{{0, 0}, {0, 1}, {1, 1}, {1, 0}}
+	for i, pt := range sym.Points {
+		quad[i] = [2]float64{float64(pt.X) + square[i][0], float64(pt.Y) + square[i][1]}
+		square[i] = [2]float64{float64(margin) + square[i][0]*float64(size), float64(margin) + square[i][1]*float64(size)}
+	}
+	h, err := NewHomography(square, quad)
+	if err != nil {
+		return nil, err
+	}
+
+	var n = size + 2*margin
+	var dst = image.NewRGBA(image.Rect(0, 0, n, n))
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			var sx, sy = h.Map(float64(x)+0.5, float64(y)+0.5)
+			dst.SetRGBA(x, y, bilinearRGBA(img, sx-0.5, sy-0.5))
+		}
+	}
+	return dst, nil
+}
+
+/** sample img at a fractional position, white outside its bounds. */
+func bilinearRGBA(img image.Image, fx, fy float64) color.RGBA {
+	var b = img.Bounds()
+	var x0, y0 = int(math.Floor(fx)), int(math.Floor(fy))
+	var ax, ay = fx - float64(x0), fy - float64(y0)
+
+	var sum [4]float64
+	for _, c := range [4]struct {
+		x, y int
+		w    float64
+	}{
+		{x0, y0, (1 - ax) * (1 - ay)},
+		{x0 + 1, y0, ax * (1 - ay)},
+		{x0, y0 + 1, (1 - ax) * ay},
+		{x0 + 1, y0 + 1, ax * ay},
+	} {
+		var r, g, bl, a uint32 = 0xffff, 0xffff, 0xffff, 0xffff
+		if (image.Point{c.x, c.y}).In(b) {
+			r, g, bl, a = img.At(c.x, c.y).RGBA()
+		}
+		sum[0] += float64(r) * c.w
+		sum[1] += float64(g) * c.w
+		sum[2] += float64(bl) * c.w
+		sum[3] += float64(a) * c.w
+	}
+	return color.RGBA{
+		R: clampByte(sum[0] / 257),
+		G: clampByte(sum[1] / 257),
+		B: clampByte(sum[2] / 257),
+		A: clampByte(sum[3] / 257),
+	}
+}
+
+/*@}*/
@@ -0,0 +1,67 @@
+package zbar
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestHomography(t *testing.T) {
+	var src = [4][2]float64{{0, 0}, {0, 1}, {1, 1}, {1, 0}}
+	var dst = [4][2]float64{{10, 10}, {12, 50}, {60, 55}, {50, 8}}
+	h, err := NewHomography(src, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range src {
+		x, y := h.Map(src[i][0], src[i][1])
+		if math.Abs(x-dst[i][0]) > 1e-9 || math.Abs(y-dst[i][1]) > 1e-9 {
+			t.Errorf("point %d maps to (%g, %g), want %v", i, x, y, dst[i])
+		}
+	}
+
+	var line = [4][2]float64{{0, 0}, {1, 1}, {2, 2}, {3, 0}}
+	if _, err := NewHomography(line, dst); err != ErrDegenerate {
+		t.Errorf("collinear points: %v", err)
+	}
+}
+
+func TestRectify(t *testing.T) {
+	// left half red, right half blue
+	var img = image.NewRGBA(image.Rect(0, 0, 40, 40))
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 40; x++ {
+			var c = color.RGBA{R: 255, A: 255}
+			if x >= 20 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+
+	// symbol turned a quarter counter-clockwise: its top left corner is
+	// at the top right of the image
+	var sym = Symbol{Points: []image.Point{{39, 0}, {0, 0}, {0, 39}, {39, 39}}}
+	out, err := Rectify(img, sym, 40, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := out.Bounds(); b.Dx() != 48 || b.Dy() != 48 {
+		t.Fatalf("bounds = %v", b)
+	}
+	// upright, the image's right (blue) half is the symbol's top half
+	if c := color.RGBAModel.Convert(out.At(24, 8)).(color.RGBA); c.B != 255 || c.R != 0 {
+		t.Errorf("top = %v", c)
+	}
+	if c := color.RGBAModel.Convert(out.At(24, 40)).(color.RGBA); c.R != 255 || c.B != 0 {
+		t.Errorf("bottom = %v", c)
+	}
+	if c := color.RGBAModel.Convert(out.At(1, 1)).(color.RGBA); c != (color.RGBA{255, 255, 255, 255}) {
+		t.Errorf("margin = %v", c)
+	}
+
+	if _, err := Rectify(img, Symbol{Points: sym.Points[:2]}, 40, 0); err != ErrNotQuad {
+		t.Errorf("two points: %v", err)
+	}
+}
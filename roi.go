@@ -0,0 +1,138 @@
+package zbar
+
+import (
+	"errors"
+	"image"
+)
+
+/*------------------------------------------------------------*/
+/** @name Region of interest scanning
+ * restricts scanning to parts of an image.  only the selected areas
+ * are converted, preprocessed and scanned; symbol locations are
+ * reported in the coordinates of the full image.  areas may also be
+ * split into an overlapping grid of tiles, which keeps small codes in
+ * large frames within the scanner's reach
+ */
+/*@{*/
+
+/** returned by NewScanner for an unusable tile size or overlap. */
+var ErrTiling = errors.New("zbar: invalid tiling")
+
+/** scan only the given regions of an image.
+ * regions are clipped to the image bounds; without any region the
+ * whole image is scanned.  the profile's own Regions are ignored, its
+ * tiling still applies.  symbols found in several (overlapping)
+ * regions are reported once.
+ */
+func (s *Scanner) ScanRegions(img image.Image, regions ...image.Rectangle) ([]Symbol, error) {
+	return s.scanRegions(img, regions, 0)
+}
+
+/** scan an image using the profile's regions and tiling. */
+func (s *Scanner) scanImage(img image.Image, sequence uint32) ([]Symbol, error) {
+	return s.scanRegions(img, s.profile.Regions, sequence)
+}
+
+/** scan regions of an image and apply the profile's policies once to
+ * the merged results.
+ */
+func (s *Scanner) scanRegions(img image.Image, regions []image.Rectangle, sequence uint32) ([]Symbol, error) {
+	symbols, err := s.scanAreas(img, regions, sequence)
+	if err != nil {
+		return nil, err
+	}
+	return s.filter(symbols), nil
+}
+
+func (s *Scanner) scanAreas(img image.Image, regions []image.Rectangle, sequence uint32) ([]Symbol, error) {
+	if len(regions) == 0 && s.profile.TileSize == 0 {
+		return s.scan(s.prepare(img), sequence)
+	}
+
+	var areas []image.Rectangle
+	for _, r := range regions {
+		if r = r.Intersect(img.Bounds()); !r.Empty() {
+			areas = append(areas, r)
+		}
+	}
+	if len(regions) == 0 {
+		areas = []image.Rectangle{img.Bounds()}
+	}
+	if s.profile.TileSize > 0 {
+		var grid []image.Rectangle
+		for _, r := range areas {
+			grid = append(grid, tiles(r, s.profile.TileSize, s.profile.TileOverlap)...)
+		}
+		areas = grid
+	}
+
+	var symbols []Symbol
+	for _, r := range areas {
+		found, err := s.scan(s.prepare(subImage(img, r)), sequence)
+		if err != nil {
+			return nil, err
+		}
+		for _, sym := range found {
+			symbols = mergeSymbol(symbols, sym)
+		}
+	}
+	return symbols, nil
+}
+
+/** check the tiling settings of a profile. */
+func validTiling(size, overlap int) error {
+	if size < 0 || overlap < 0 || (size > 0 && overlap >= size) {
+		return ErrTiling
+	}
+	return nil
+}
+
+/** split r into a grid of size x size tiles overlapping by overlap
+ * pixels.  the last tile of each row and column is aligned with the
+ * edge of r, so tiles never extend beyond it.
+ */
+func tiles(r image.Rectangle, size, overlap int) []image.Rectangle {
+	var xs = tileStarts(r.Min.X, r.Max.X, size, size-overlap)
+	var ys = tileStarts(r.Min.Y, r.Max.Y, size, size-overlap)
+
+	var grid = make([]image.Rectangle, 0, len(xs)*len(ys))
+	for _, y := range ys {
+		for _, x := range xs {
+			grid = append(grid, image.Rect(x, y, x+size, y+size).Intersect(r))
+		}
+	}
+	return grid
+}
+
+func tileStarts(min, max, size, step int) []int {
+	var starts = []int{min}
+	for start := min; start+size < max; {
+		if start += step; start+size > max {
+			start = max - size
+		}
+		starts = append(starts, start)
+	}
+	return starts
+}
+
+/** area of an image, keeping the image's coordinate space. */
+func subImage(img image.Image, r image.Rectangle) image.Image {
+	if sub, ok := img.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok {
+		return sub.SubImage(r)
+	}
+	return cropped{img, r}
+}
+
+/** image view limited to a rectangle, for images without SubImage. */
+type cropped struct {
+	image.Image
+	rect image.Rectangle
+}
+
+func (c cropped) Bounds() image.Rectangle {
+	return c.rect
+}
+
+/*@}*/
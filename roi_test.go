@@ -0,0 +1,59 @@
+package zbar
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestTiles(t *testing.T) {
+	var grid = tiles(image.Rect(10, 0, 260, 100), 100, 20)
+	var want = []image.Rectangle{
+		image.Rect(10, 0, 110, 100),
+		image.Rect(90, 0, 190, 100),
+		image.Rect(160, 0, 260, 100),
+	}
+	if len(grid) != len(want) {
+		t.Fatalf("tiles = %v", grid)
+	}
+	for i := range want {
+		if grid[i] != want[i] {
+			t.Errorf("tile %d = %v, want %v", i, grid[i], want[i])
+		}
+	}
+
+	// areas smaller than a tile become a single tile
+	if grid = tiles(image.Rect(0, 0, 50, 30), 100, 20); len(grid) != 1 || grid[0] != image.Rect(0, 0, 50, 30) {
+		t.Errorf("small area tiles = %v", grid)
+	}
+
+	for _, bad := range [][2]int{{-1, 0}, {100, 100}, {100, -5}} {
+		if validTiling(bad[0], bad[1]) == nil {
+			t.Errorf("tiling %v accepted", bad)
+		}
+	}
+	if validTiling(0, 0) != nil || validTiling(64, 16) != nil {
+		t.Error("valid tiling rejected")
+	}
+}
+
+func TestSubImage(t *testing.T) {
+	var img = image.NewRGBA(image.Rect(0, 0, 40, 40))
+	img.Set(25, 12, color.Black)
+
+	var r = image.Rect(20, 10, 30, 20)
+	for _, src := range []image.Image{img, opaque{img}} {
+		var gray = toGray(subImage(src, r))
+		if gray.Bounds() != r {
+			t.Fatalf("%T bounds = %v", src, gray.Bounds())
+		}
+		if v := gray.GrayAt(25, 12).Y; v != 0 {
+			t.Errorf("%T pixel = %d", src, v)
+		}
+	}
+}
+
+/** image hiding the SubImage method of the wrapped image. */
+type opaque struct {
+	image.Image
+}
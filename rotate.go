@@ -0,0 +1,120 @@
+package zbar
+
+import (
+	"image"
+	"math"
+)
+
+/*------------------------------------------------------------*/
+/** @name Multi-angle scanning
+ * the image scanner only samples horizontal and vertical scan lines
+ * (ZBAR_CFG_X_DENSITY / ZBAR_CFG_Y_DENSITY), so dense linear codes at
+ * steep angles are missed.  when a profile lists retry angles, images
+ * without results are rotated by each angle in turn and rescanned;
+ * the location of symbols found this way is mapped back into the
+ * coordinates of the original image
+ */
+/*@{*/
+
+/** scan with angle retries.
+ * angles are tried in the order given, stopping at the first one
+ * that yields any symbol accepted by the profile's policies.  if none
+ * does, the reads of the unrotated pass are returned.
+ */
+func (s *Scanner) scanRotated(gray *image.Gray, sequence uint32) ([]Symbol, error) {
+	first, err := s.scanPolarity(gray, sequence)
+	if err != nil || s.accepts(first) {
+		return first, err
+	}
+
+	for _, angle := range s.profile.Rotate {
+		if math.Mod(angle, 360) == 0 {
+			continue
+		}
+
+		rotated, back := rotateGray(gray, angle)
+		symbols, err := s.scanPolarity(rotated, sequence)
+		if err != nil {
+			return nil, err
+		}
+		if s.accepts(symbols) {
+			var quarters = int(math.Round(angle / 90))
+			for i := range symbols {
+				for j, pt := range symbols[i].Points {
+					symbols[i].Points[j] = back(pt)
+				}
+				symbols[i].Orientation = symbols[i].Orientation.turn(-quarters)
+			}
+			return symbols, nil
+		}
+	}
+
+	return first, nil
+}
+
+/** rotate an image by degrees around its centre.
+ * with image coordinates (y pointing down) positive angles turn the
+ * content clockwise.  the result is enlarged to hold the whole source
+ * and uncovered areas are filled white, so no artificial edges are
+ * introduced.  samples are interpolated bilinearly.
+ * @returns the rotated image, with bounds starting at (0,0), and a
+ * function mapping its points back into the source coordinates
+ */
+func rotateGray(src *image.Gray, degrees float64) (*image.Gray, func(image.Point) image.Point) {
+	var rad = degrees * math.Pi / 180
+	var sin, cos = math.Sincos(rad)
+
+	var sb = src.Bounds()
+	var sw, sh = float64(sb.Dx()), float64(sb.Dy())
+	// the epsilon keeps right angles from growing by a pixel
+	var dw = int(math.Ceil(math.Abs(sw*cos) + math.Abs(sh*sin) - 1e-9))
+	var dh = int(math.Ceil(math.Abs(sw*sin) + math.Abs(sh*cos) - 1e-9))
+
+	// centres of source (in source coordinates) and destination
+	var scx, scy = float64(sb.Min.X) + sw/2, float64(sb.Min.Y) + sh/2
+	var dcx, dcy = float64(dw) / 2, float64(dh) / 2
+
+	var dst = image.NewGray(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		var ry = float64(y) + 0.5 - dcy
+		for x := 0; x < dw; x++ {
+			var rx = float64(x) + 0.5 - dcx
+			// inverse rotation into the source
+			var fx = rx*cos + ry*sin + scx - 0.5
+			var fy = -rx*sin + ry*cos + scy - 0.5
+			dst.Pix[y*dst.Stride+x] = bilinear(src, fx, fy)
+		}
+	}
+
+	var back = func(p image.Point) image.Point {
+		var rx, ry = float64(p.X) + 0.5 - dcx, float64(p.Y) + 0.5 - dcy
+		return image.Pt(
+			int(math.Floor(rx*cos+ry*sin+scx)),
+			int(math.Floor(-rx*sin+ry*cos+scy)),
+		)
+	}
+	return dst, back
+}
+
+/** sample src at a fractional position, white outside its bounds. */
+func bilinear(src *image.Gray, fx, fy float64) uint8 {
+	var b = src.Bounds()
+	var x0, y0 = int(math.Floor(fx)), int(math.Floor(fy))
+	if x0 < b.Min.X-1 || y0 < b.Min.Y-1 || x0 >= b.Max.X || y0 >= b.Max.Y {
+		return 0xff
+	}
+
+	var at = func(x, y int) float64 {
+		if x < b.Min.X || y < b.Min.Y || x >= b.Max.X || y >= b.Max.Y {
+			return 0xff
+		}
+		return float64(src.Pix[(y-b.Min.Y)*src.Stride+x-b.Min.X])
+	}
+
+	var ax, ay = fx - float64(x0), fy - float64(y0)
+	var top = at(x0, y0)*(1-ax) + at(x0+1, y0)*ax
+	var bottom = at(x0, y0+1)*(1-ax) + at(x0+1, y0+1)*ax
+	return clampByte(top*(1-ay) + bottom*ay)
+}
+
+/*@}*/
@@ -0,0 +1,35 @@
+package zbar
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestRotateGray(t *testing.T) {
+	var src = image.NewGray(image.Rect(10, 20, 50, 40))
+	for i := range src.Pix {
+		src.Pix[i] = 0xff
+	}
+	src.SetGray(15, 22, color.Gray{Y: 0})
+
+	rotated, back := rotateGray(src, 90)
+	if b := rotated.Bounds(); b.Dx() != 20 || b.Dy() != 40 {
+		t.Fatalf("bounds = %v", b)
+	}
+
+	// clockwise quarter turn: (x, y) -> (h-1-y, x) relative to the source origin
+	var want = image.Pt(20-1-2, 5)
+	if v := rotated.GrayAt(want.X, want.Y).Y; v != 0 {
+		t.Errorf("rotated pixel = %d", v)
+	}
+	if p := back(want); p != image.Pt(15, 22) {
+		t.Errorf("back = %v", p)
+	}
+
+	// corners are filled white
+	rotated, _ = rotateGray(src, 45)
+	if v := rotated.GrayAt(0, 0).Y; v != 0xff {
+		t.Errorf("corner = %d", v)
+	}
+}
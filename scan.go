@@ -0,0 +1,323 @@
+package zbar
+
+// #include <stdlib.h>
+// #include <zbar.h>
+import "C"
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"unsafe"
+
+	"github.com/zooyer/zbar/isbn"
+)
+
+/*------------------------------------------------------------*/
+/** @name High level scanning interface
+ * scans Go images with a configured image scanner and copies the
+ * decoded results out of library owned memory
+ */
+/*@{*/
+
+/** returned by Scanner.Scan when zbar_scan_image() reports an error. */
+var ErrScan = errors.New("zbar: scan failed")
+
+/** "Y800" fourcc: 8-bit grayscale samples, one byte per pixel. */
+var fourccY800 = ZBarFourcc('Y', '8', '0', '0')
+
+/** names of symbologies unknown to older libraries. */
+var symbolNames = map[ZBarSymbolType]string{
+	ZBAR_EAN2:        "EAN-2",
+	ZBAR_EAN5:        "EAN-5",
+	ZBAR_COMPOSITE:   "COMPOSITE",
+	ZBAR_DATABAR:     "DataBar",
+	ZBAR_DATABAR_EXP: "DataBar-Exp",
+	ZBAR_CODABAR:     "Codabar",
+	ZBAR_SQCODE:      "SQ-Code",
+	ZBAR_CODE93:      "CODE-93",
+}
+
+/** symbol type name, see zbar_get_symbol_name().
+ * newer symbologies are named even if the library does not know them.
+ */
+func (sym ZBarSymbolType) String() string {
+	if name := ZBarGetSymbolName(sym); name != "" && name != "UNKNOWN" {
+		return name
+	}
+	if name, ok := symbolNames[sym]; ok {
+		return name
+	}
+	return "UNKNOWN"
+}
+
+/** named image scanner configuration.
+ * each entry of Configs is a config string of the form
+ * "[symbology.]config[=value]", see zbar_parse_config(); the entries
+ * are applied in order to a freshly created image scanner.
+ * Preprocess lists the preprocessing stages run on every image before
+ * it is scanned, see ParsePreprocess().
+ * Rotate lists angles in degrees to rotate and rescan an image by when
+ * the first pass finds nothing, eg [15, -15, 30, -30, 45].
+ * Scales lists additional resolutions to scan the image at, eg
+ * [0.5, 0.25, 2], within a budget of ScaleBudgetMS milliseconds
+ * (0 for no limit).
+ * Regions restricts scanning to parts of each image; TileSize (with
+ * TileOverlap pixels of overlap) splits the regions, or the whole
+ * image, into square tiles that are scanned separately.
+ * Invert selects whether light on dark symbols are searched for too,
+ * see InvertRetry and InvertBoth.
+ * I25 restricts the Interleaved 2 of 5 reads that are accepted.
+ * PDFMaxPixels limits the size of images decoded from PDF documents
+ * (0 for pdf.DefaultMaxPixels).
+ */
+type Profile struct {
+	Name          string            `json:"name"`
+	Configs       []string          `json:"configs"`
+	Preprocess    []string          `json:"preprocess,omitempty"`
+	Rotate        []float64         `json:"rotate,omitempty"`
+	Scales        []float64         `json:"scales,omitempty"`
+	ScaleBudgetMS int               `json:"scale_budget_ms,omitempty"`
+	Regions       []image.Rectangle `json:"regions,omitempty"`
+	TileSize      int               `json:"tile_size,omitempty"`
+	TileOverlap   int               `json:"tile_overlap,omitempty"`
+	Invert        string            `json:"invert,omitempty"`
+	I25           *I25Policy        `json:"i25,omitempty"`
+	PDFMaxPixels  int               `json:"pdf_max_pixels,omitempty"`
+}
+
+/** decoded symbol result.
+ * unlike ZBarSymbol all fields are owned by Go and remain valid after
+ * the scanned image is destroyed.
+ * Sequence is the sequence (page/frame) number of the scanned image.
+ * Orientation is the reading direction, see ZBarOrientation.
+ * Modifiers is the bitmask of ZBarModifier flags (libzbar 0.11+).
+ * Inverted is set for light on dark symbols.
+ * Main and AddOn split the data of EAN/UPC symbols with a 2 or 5
+ * digit add-on, whose type (ZBAR_ADDON2, ZBAR_ADDON5, ZBAR_EAN2 or
+ * ZBAR_EAN5) is AddOnType; for other symbols Main is Data.
+ * ISBN is set for ISBN symbols and EAN-13 symbols in the 978/979
+ * Bookland ranges.
+ */
+type Symbol struct {
+	Type        ZBarSymbolType
+	Data        string
+	Quality     int
+	Points      []image.Point
+	Orientation ZBarOrientation
+	Modifiers   uint32
+	Sequence    int
+	Inverted    bool
+	Main        string
+	AddOn       string
+	AddOnType   ZBarSymbolType
+	ISBN        *isbn.ISBN
+}
+
+/** bounding box of the symbol location. */
+func (sym Symbol) Bounds() image.Rectangle {
+	var r image.Rectangle
+	for i, pt := range sym.Points {
+		if i == 0 {
+			r = image.Rectangle{Min: pt, Max: pt.Add(image.Pt(1, 1))}
+		} else {
+			r = r.Union(image.Rectangle{Min: pt, Max: pt.Add(image.Pt(1, 1))})
+		}
+	}
+	return r
+}
+
+/** check whether a modifier flag is set. */
+func (sym Symbol) HasModifier(mod ZBarModifier) bool {
+	return sym.Modifiers&(1<<uint(mod)) != 0
+}
+
+/** high level image scanner.
+ * wraps a ZBarImageScanner configured from a Profile.
+ * @note a Scanner is not safe for concurrent use
+ */
+type Scanner struct {
+	profile  Profile
+	pipeline Pipeline
+	scanner  *ZBarImageScanner
+	rejects  Rejects
+	decode   func(gray *image.Gray, sequence uint32) ([]Symbol, error) // single pass, scanGray unless replaced in tests
+}
+
+/** constructor.
+ * a nil profile leaves the library defaults untouched.
+ * @returns an error naming the first config string that failed
+ */
+func NewScanner(profile *Profile) (*Scanner, error) {
+	var s = &Scanner{scanner: ZBarImageScannerCreate()}
+	if profile != nil {
+		s.profile = *profile
+	}
+
+	if err := validTiling(s.profile.TileSize, s.profile.TileOverlap); err != nil {
+		s.Close()
+		return nil, err
+	}
+	if err := validInvert(s.profile.Invert); err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	for _, spec := range s.profile.Preprocess {
+		stage, err := ParsePreprocess(spec)
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+		s.pipeline = append(s.pipeline, stage)
+	}
+
+	for _, cfg := range s.profile.Configs {
+		if ZBarImageScannerParseConfig(s.scanner, cfg) != 0 {
+			s.Close()
+			return nil, fmt.Errorf("zbar: invalid config %q", cfg)
+		}
+	}
+
+	if s.profile.I25 != nil {
+		if err := s.profile.I25.configure(s.scanner); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+/** destructor. */
+func (s *Scanner) Close() {
+	if s.scanner != nil {
+		ZBarImageScannerDestroy(s.scanner)
+		s.scanner = nil
+	}
+}
+
+/** profile the scanner was configured from. */
+func (s *Scanner) Profile() Profile {
+	return s.profile
+}
+
+/** scan an image for symbols.
+ * the image (or the profile's regions of it) is converted to 8-bit
+ * grayscale and run through the profile's preprocessing stages first.
+ * @returns the (possibly empty) list of decoded symbols
+ */
+func (s *Scanner) Scan(img image.Image) ([]Symbol, error) {
+	return s.scanImage(img, 0)
+}
+
+/** grayscale conversion and preprocessing. */
+func (s *Scanner) prepare(img image.Image) *image.Gray {
+	return s.pipeline.Apply(s.pipeline.Gray(img))
+}
+
+/** scan a prepared image using the strategies enabled by the profile. */
+func (s *Scanner) scan(gray *image.Gray, sequence uint32) ([]Symbol, error) {
+	return s.scanPyramid(gray, sequence)
+}
+
+/** scan a grayscale image tagged with the given sequence number. */
+func (s *Scanner) scanGray(gray *image.Gray, sequence uint32) ([]Symbol, error) {
+	var bounds = gray.Bounds()
+	var width, height = bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return nil, nil
+	}
+
+	var zimg = ZBarImageCreate()
+	defer ZBarImageDestroy(zimg)
+
+	ZBarImageSetFormat(zimg, fourccY800)
+	ZBarImageSetSize(zimg, uint32(width), uint32(height))
+	ZBarImageSetSequence(zimg, sequence)
+	setImageData(zimg, y800(gray))
+
+	if ZBarScanImage(s.scanner, zimg) < 0 {
+		return nil, ErrScan
+	}
+
+	var seq = int(ZBarImageGetSequence(zimg))
+	var symbols []Symbol
+	for sym := ZBarImageFirstSymbol(zimg); sym != nil; sym = ZBarSymbolNext(sym) {
+		var symbol = newSymbol(sym, bounds.Min)
+		symbol.Sequence = seq
+		symbols = append(symbols, symbol)
+	}
+
+	return symbols, nil
+}
+
+/** run a single scanning pass over a prepared image. */
+func (s *Scanner) pass(gray *image.Gray, sequence uint32) ([]Symbol, error) {
+	if s.decode != nil {
+		return s.decode(gray, sequence)
+	}
+	return s.scanGray(gray, sequence)
+}
+
+/** copy a library symbol, translating its location by offset. */
+func newSymbol(sym *ZBarSymbol, offset image.Point) Symbol {
+	var n = ZBarSymbolGetLocSize(sym)
+	var points = make([]image.Point, 0, n)
+	for i := uint32(0); i < n; i++ {
+		points = append(points, image.Pt(ZBarSymbolGetLocX(sym, i), ZBarSymbolGetLocY(sym, i)).Add(offset))
+	}
+
+	var symbol = Symbol{
+		Type:        ZBarSymbolGetType(sym),
+		Data:        symbolData(sym),
+		Quality:     ZBarSymbolGetQuality(sym),
+		Points:      points,
+		Orientation: symbolOrientation(sym, points),
+		Modifiers:   ZBarSymbolGetModifiers(sym),
+	}
+	splitAddOn(&symbol, sym)
+	symbol.ISBN = symbolISBN(symbol)
+	return symbol
+}
+
+/** retrieve the complete (possibly binary) symbol data. */
+func symbolData(sym *ZBarSymbol) string {
+	var csym = (*C.zbar_symbol_t)(unsafe.Pointer(sym))
+	return C.GoStringN(C.zbar_symbol_get_data(csym), C.int(C.zbar_symbol_get_data_length(csym)))
+}
+
+/** hand a copy of data to the image.
+ * the copy lives in C memory and is released with free() by the
+ * built-in zbar_image_free_data() cleanup handler.
+ */
+func setImageData(img *ZBarImage, data []byte) {
+	C.zbar_image_set_data((*C.zbar_image_t)(unsafe.Pointer(img)), C.CBytes(data), C.ulong(len(data)), (*C.zbar_image_cleanup_handler_t)(C.zbar_image_free_data))
+}
+
+/** convert any image to 8-bit grayscale. */
+func toGray(img image.Image) *image.Gray {
+	if gray, ok := img.(*image.Gray); ok {
+		return gray
+	}
+
+	var gray = image.NewGray(img.Bounds())
+	draw.Draw(gray, gray.Rect, img, gray.Rect.Min, draw.Src)
+	return gray
+}
+
+/** tightly packed Y800 samples of a grayscale image. */
+func y800(gray *image.Gray) []byte {
+	var width, height = gray.Rect.Dx(), gray.Rect.Dy()
+	if gray.Stride == width {
+		return gray.Pix[:width*height]
+	}
+
+	var data = make([]byte, width*height)
+	for y := 0; y < height; y++ {
+		copy(data[y*width:(y+1)*width], gray.Pix[y*gray.Stride:])
+	}
+	return data
+}
+
+/*@}*/
@@ -0,0 +1,88 @@
+package zbar
+
+import (
+	"image"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+/** render an EAN-13 symbol with module width scale and quiet zones. */
+func renderEAN13(digits string, scale int) *image.Gray {
+	var l = []string{"0001101", "0011001", "0010011", "0111101", "0100011", "0110001", "0101111", "0111011", "0110111", "0001011"}
+	var parity = []string{"LLLLLL", "LLGLGG", "LLGGLG", "LLGGGL", "LGLLGG", "LGGLLG", "LGGGLL", "LGLGLG", "LGLGGL", "LGGLGL"}
+	var invert = func(s string) string {
+		return strings.NewReplacer("0", "1", "1", "0").Replace(s)
+	}
+	var reverse = func(s string) string {
+		var b = []byte(s)
+		for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+			b[i], b[j] = b[j], b[i]
+		}
+		return string(b)
+	}
+
+	var bits = "101"
+	for i, c := range digits[1:7] {
+		var code = l[c-'0']
+		if parity[digits[0]-'0'][i] == 'G' {
+			code = reverse(invert(code))
+		}
+		bits += code
+	}
+	bits += "01010"
+	for _, c := range digits[7:] {
+		bits += invert(l[c-'0'])
+	}
+	bits += "101"
+
+	var quiet = 11 * scale
+	var img = image.NewGray(image.Rect(0, 0, len(bits)*scale+2*quiet, 40*scale))
+	for y := 0; y < img.Rect.Dy(); y++ {
+		for x := 0; x < img.Rect.Dx(); x++ {
+			var i = (x - quiet) / scale
+			var v uint8 = 0xff
+			if x >= quiet && i < len(bits) && bits[i] == '1' {
+				v = 0
+			}
+			img.Pix[y*img.Stride+x] = v
+		}
+	}
+	return img
+}
+
+func TestScanLibrary(t *testing.T) {
+	var major, minor uint32
+	if ZBarVersion(&major, &minor); major == 0 && minor == 0 {
+		t.Skip("linked library reports no version")
+	}
+
+	s, err := NewScanner(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	var calls int
+	var tag byte
+	ZBarImageScannerSetDataHandler(s.scanner, func(image *ZBarImage, userData unsafe.Pointer) {
+		if userData == unsafe.Pointer(&tag) {
+			calls++
+		}
+	}, unsafe.Pointer(&tag))
+
+	symbols, err := s.Scan(renderEAN13("4006381333931", 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(symbols) != 1 || symbols[0].Type != ZBAR_EAN13 || symbols[0].Data != "4006381333931" {
+		t.Fatalf("symbols = %+v", symbols)
+	}
+	if calls != 1 {
+		t.Errorf("data handler called %d times", calls)
+	}
+
+	if prev := ZBarImageScannerSetDataHandler(s.scanner, nil, nil); prev == nil {
+		t.Error("previous handler not returned")
+	}
+}
@@ -1,16 +1,16 @@
 package zbar
 
-// #cgo LDFLAGS: -lzbar
-// #cgo CFLAGS: -IE:/SoftWare/ZBar/include
-// #cgo 386   LDFLAGS: -L E:/SoftWare/ZBarWin64/lib   -lzbar-0
-// #cgo amd64 LDFLAGS: -L E:/SoftWare/ZBarWin64/lib   -lzbar64-0
+// #cgo !windows LDFLAGS: -lzbar
+// #cgo windows CFLAGS: -IE:/SoftWare/ZBar/include
+// #cgo windows LDFLAGS: -lzbar
+// #cgo windows,386   LDFLAGS: -L E:/SoftWare/ZBarWin64/lib   -lzbar-0
+// #cgo windows,amd64 LDFLAGS: -L E:/SoftWare/ZBarWin64/lib   -lzbar64-0
 // #include <zbar.h>
 import "C"
 import (
 	"fmt"
 	"reflect"
 	"unsafe"
-	"syscall"
 )
 
 /** "color" of element: bar or space. */
@@ -25,16 +25,24 @@ type ZBarSymbolType int
 const (
 	ZBAR_NONE        ZBarSymbolType =      0 + iota  /**< no symbol decoded */
 	ZBAR_PARTIAL     ZBarSymbolType =      1  /**< intermediate status */
+	ZBAR_EAN2        ZBarSymbolType =      2  /**< GS1 2-digit add-on. @since 0.11 */
+	ZBAR_EAN5        ZBarSymbolType =      5  /**< GS1 5-digit add-on. @since 0.11 */
 	ZBAR_EAN8        ZBarSymbolType =      8  /**< EAN-8 */
 	ZBAR_UPCE        ZBarSymbolType =      9  /**< UPC-E */
 	ZBAR_ISBN10      ZBarSymbolType =     10  /**< ISBN-10 (from EAN-13). @since 0.4 */
 	ZBAR_UPCA        ZBarSymbolType =     12  /**< UPC-A */
 	ZBAR_EAN13       ZBarSymbolType =     13  /**< EAN-13 */
 	ZBAR_ISBN13      ZBarSymbolType =     14  /**< ISBN-13 (from EAN-13). @since 0.4 */
+	ZBAR_COMPOSITE   ZBarSymbolType =     15  /**< EAN/UPC composite. @since 0.11 */
 	ZBAR_I25         ZBarSymbolType =     25  /**< Interleaved 2 of 5. @since 0.4 */
+	ZBAR_DATABAR     ZBarSymbolType =     34  /**< GS1 DataBar (RSS). @since 0.11 */
+	ZBAR_DATABAR_EXP ZBarSymbolType =     35  /**< GS1 DataBar Expanded. @since 0.11 */
+	ZBAR_CODABAR     ZBarSymbolType =     38  /**< Codabar. @since 0.11 */
 	ZBAR_CODE39      ZBarSymbolType =     39  /**< Code 39. @since 0.4 */
 	ZBAR_PDF417      ZBarSymbolType =     57  /**< PDF417. @since 0.6 */
 	ZBAR_QRCODE      ZBarSymbolType =     64  /**< QR Code. @since 0.10 */
+	ZBAR_SQCODE      ZBarSymbolType =     80  /**< SQ Code. @since 0.23 */
+	ZBAR_CODE93      ZBarSymbolType =     93  /**< Code 93. @since 0.11 */
 	ZBAR_CODE128     ZBarSymbolType =    128  /**< Code 128 */
 	ZBAR_SYMBOL      ZBarSymbolType = 0x00ff  /**< mask for base symbol type */
 	ZBAR_ADDON2      ZBarSymbolType = 0x0200  /**< 2-digit add-on flag */
@@ -71,16 +79,41 @@ const (
 	ZBAR_CFG_ASCII                                    /**< enable full ASCII character set */
 	ZBAR_CFG_NUM                                      /**< number of boolean decoder configs */
 )
+/** don't convert binary data to text.
+ * the value is ZBAR_CFG_NUM before 0.23, so the set config functions
+ * reject it when the loaded library is older (see zbar_version()).
+ * @since 0.23, where ZBAR_CFG_NUM becomes 5
+ */
+const ZBAR_CFG_BINARY ZBarConfig = 4
 const (
 	ZBAR_CFG_MIN_LEN ZBarConfig = 0x20 + iota        /**< minimum data length for valid decode */
 	ZBAR_CFG_MAX_LEN                                 /**< maximum data length for valid decode */
+	ZBAR_CFG_UNCERTAINTY ZBarConfig = 0x40           /**< required video consistency frames. @since 0.11 */
 	ZBAR_CFG_POSITION ZBarConfig = 0x80              /**< enable scanner to collect position data */
+	ZBAR_CFG_TEST_INVERTED ZBarConfig = 0x81         /**< if fails to decode, test inverted. @since 0.23 */
 )
 const (
 	ZBAR_CFG_X_DENSITY ZBarConfig = 0x100 + iota    /**< image scanner vertical scan density */
 	ZBAR_CFG_Y_DENSITY                               /**< image scanner horizontal scan density */
 )
 
+/** decoder symbology modifier flags.
+ * @since 0.11
+ */
+type ZBarModifier int
+const (
+	/** barcode tagged as GS1 (EAN.UCC) reserved
+	 * (eg, FNC1 before first data character).
+	 * data may be parsed as a sequence of GS1 AIs
+	 */
+	ZBAR_MOD_GS1 ZBarModifier = iota
+	/** barcode tagged as AIM reserved
+	 * (eg, FNC1 after first character or digit pair)
+	 */
+	ZBAR_MOD_AIM
+	ZBAR_MOD_NUM                                      /**< number of modifiers */
+)
+
 /** retrieve runtime library version information.
  * @param major set to the running major version (unless NULL)
  * @param minor set to the running minor version (unless NULL)
@@ -257,7 +290,7 @@ func ZBarSymbolGetLocY(symbol *ZBarSymbol, index uint32) int {
  * @returns NULL when no more results are available
  */
 func ZBarSymbolNext(symbol *ZBarSymbol) *ZBarSymbol {
-	return (*ZBarSymbol)(C.zbar_symbol_next((*C.zbar_symbol_t)(unsafe.Pointer(symbol))))
+	return (*ZBarSymbol)(unsafe.Pointer(C.zbar_symbol_next((*C.zbar_symbol_t)(unsafe.Pointer(symbol)))))
 }
 
 /** retrieve components of a composite result.
@@ -266,7 +299,7 @@ func ZBarSymbolNext(symbol *ZBarSymbol) *ZBarSymbol {
  * @since 0.10
  */
 func ZBarSymbolGetComponents(symbol *ZBarSymbol) *ZBarSymbolSet {
-	return (*ZBarSymbolSet)(C.zbar_symbol_get_components((*C.zbar_symbol_t)(unsafe.Pointer(symbol))))
+	return (*ZBarSymbolSet)(unsafe.Pointer(C.zbar_symbol_get_components((*C.zbar_symbol_t)(unsafe.Pointer(symbol)))))
 }
 
 /** iterate components of a composite result.
@@ -275,7 +308,7 @@ func ZBarSymbolGetComponents(symbol *ZBarSymbol) *ZBarSymbolSet {
  * @since 0.10
  */
 func ZBarSymbolFirstComponent(symbol *ZBarSymbol) *ZBarSymbol {
-	return (*ZBarSymbol)(C.zbar_symbol_first_component((*C.zbar_symbol_t)(unsafe.Pointer(symbol))))
+	return (*ZBarSymbol)(unsafe.Pointer(C.zbar_symbol_first_component((*C.zbar_symbol_t)(unsafe.Pointer(symbol)))))
 }
 
 /** print XML symbol element representation to user result buffer.
@@ -330,7 +363,7 @@ func ZBarSymbolSetGetSize(symbols *ZBarSymbolSet) int {
  * @since 0.10
  */
 func ZBarSymbolSetFirstSymbol(symbols *ZBarSymbolSet) *ZBarSymbol {
-	return (*ZBarSymbol)(C.zbar_symbol_set_first_symbol((*C.zbar_symbol_set_t)(unsafe.Pointer(symbols))))
+	return (*ZBarSymbol)(unsafe.Pointer(C.zbar_symbol_set_first_symbol((*C.zbar_symbol_set_t)(unsafe.Pointer(symbols)))))
 }
 
 /*@}*/
@@ -342,6 +375,13 @@ func ZBarSymbolSetFirstSymbol(symbols *ZBarSymbolSet) *ZBarSymbol {
  */
 /*@{*/
 
+/** "fourcc" image format code construction.
+ * equivalent of the zbar_fourcc() macro, eg ZBarFourcc('Y','8','0','0')
+ */
+func ZBarFourcc(a, b, c, d byte) uint64 {
+	return uint64(a) | uint64(b)<<8 | uint64(c)<<16 | uint64(d)<<24
+}
+
 /** opaque image object. */
 type ZBarImage struct{}
 
@@ -361,7 +401,7 @@ type ZBarImageDataHandler func(image *ZBarImage, userData unsafe.Pointer)
  * soon as the application is finished with it
  */
 func ZBarImageCreate() *ZBarImage {
-	return (*ZBarImage)(C.zbar_image_create())
+	return (*ZBarImage)(unsafe.Pointer(C.zbar_image_create()))
 }
 
 /** image destructor.  all images created by or returned to the
@@ -397,7 +437,7 @@ func ZBarImageRef(image *ZBarImage, refs int) {
  * constraints
  */
 func ZBarImageConvert(image *ZBarImage, format uint64) *ZBarImage {
-	return (*ZBarImage)(C.zbar_image_convert((*C.zbar_image_t)(unsafe.Pointer(image)), (C.ulong)(format)))
+	return (*ZBarImage)(unsafe.Pointer(C.zbar_image_convert((*C.zbar_image_t)(unsafe.Pointer(image)), (C.ulong)(format))))
 }
 
 /** image format conversion with crop/pad.
@@ -412,7 +452,7 @@ func ZBarImageConvert(image *ZBarImage, format uint64) *ZBarImage {
  * @since 0.4
  */
 func ZBarImageConvertResize(image *ZBarImage, format uint64, width, height uint32) *ZBarImage {
-	return (*ZBarImage)(C.zbar_image_convert_resize((*C.zbar_image_t)(unsafe.Pointer(image)), C.ulong(format), C.uint(width), C.uint(height)))
+	return (*ZBarImage)(unsafe.Pointer(C.zbar_image_convert_resize((*C.zbar_image_t)(unsafe.Pointer(image)), C.ulong(format), C.uint(width), C.uint(height))))
 }
 
 /** retrieve the image format.
@@ -463,7 +503,7 @@ func ZBarImageGetDataLength(img *ZBarImage) uint64 {
  * @since 0.10
  */
 func ZBarImageGetSymbols(image *ZBarImage) *ZBarSymbolSet {
-	return (*ZBarSymbolSet)(C.zbar_image_get_symbols((*C.zbar_image_t)(unsafe.Pointer(image))))
+	return (*ZBarSymbolSet)(unsafe.Pointer(C.zbar_image_get_symbols((*C.zbar_image_t)(unsafe.Pointer(image)))))
 }
 
 /** associate the specified symbol set with the image, replacing any
@@ -481,7 +521,7 @@ func ZBarImageSetSymbols(img *ZBarImage, symbols *ZBarSymbolSet) {
  * or NULL if no results are available
  */
 func ZBarImageFirstSymbol(image *ZBarImage) *ZBarSymbol {
-	return (*ZBarSymbol)(C.zbar_image_first_symbol((*C.zbar_image_t)(unsafe.Pointer(image))))
+	return (*ZBarSymbol)(unsafe.Pointer(C.zbar_image_first_symbol((*C.zbar_image_t)(unsafe.Pointer(image)))))
 }
 
 /** specify the fourcc image format code for image sample data.
@@ -513,9 +553,7 @@ func ZBarImageSetSize(img *ZBarImage, width, height uint32) {
  * @note application image data will not be modified by the library
  */
 func ZBarImageSetData(image *ZBarImage, data unsafe.Pointer, dataByteLength uint64, cleanupHandler ZBarImageCleanupHandler) {
-	// TODO...
-
-	C.zbar_image_set_data((*C.zbar_image_t)(unsafe.Pointer(image)), data, C.ulong(dataByteLength), (*C.zbar_image_cleanup_handler_t)(unsafe.Pointer(syscall.NewCallback(cleanupHandler))))
+	setImageCleanup(image, data, dataByteLength, cleanupHandler)
 }
 
 /** built-in cleanup handler.
@@ -570,7 +608,7 @@ func ZBarImageWrite(image *ZBarImage, fileBase string) int {
  * @note TBD
  */
 func ZBarImageRead(filename string) *ZBarImage {
-	return (*ZBarImage)(C.zbar_image_read(C.CString(filename)))
+	return (*ZBarImage)(unsafe.Pointer(C.zbar_image_read(C.CString(filename))))
 }
 
 /*@}*/
@@ -594,13 +632,14 @@ type ZBarProcessor ZBarProcessorStruct
  * improve responsiveness
  */
 func ZBarProcessorCreate(threaded int) *ZBarProcessor {
-	return (*ZBarProcessor)(C.zbar_processor_create(C.int(threaded)))
+	return (*ZBarProcessor)(unsafe.Pointer(C.zbar_processor_create(C.int(threaded))))
 }
 
 /** destructor.  cleans up all resources associated with the processor
  */
 func ZBarProcessorDestroy(processor *ZBarProcessor) {
 	C.zbar_processor_destroy((*C.zbar_processor_t)(unsafe.Pointer(processor)))
+	forgetHandlers(unsafe.Pointer(processor))
 }
 
 /** (re)initialization.
@@ -660,17 +699,18 @@ func ZBarProcessorForceFormat(processor *ZBarProcessor, inputFormat, outputForma
  * @returns the previously registered handler
  */
 func ZBarProcessorSetDataHandler(processor *ZBarProcessor, handler ZBarImageDataHandler, userData unsafe.Pointer) ZBarImageDataHandler {
-	// TODO...
-	var handle = C.zbar_processor_set_data_handler((*C.zbar_processor_t)(unsafe.Pointer(processor)), (*C.zbar_image_data_handler_t)(unsafe.Pointer(syscall.NewCallback(handler))), userData)
-	return func(image *ZBarImage, userData unsafe.Pointer){
-		handle()
-	}
+	return setDataHandler(unsafe.Pointer(processor), handler, userData, func(fn *C.zbar_image_data_handler_t, userData unsafe.Pointer) {
+		C.zbar_processor_set_data_handler((*C.zbar_processor_t)(unsafe.Pointer(processor)), fn, userData)
+	})
 }
 
 /** associate user specified data value with the processor.
  * @since 0.6
  */
 func ZBarProcessorSetUserData(processor *ZBarProcessor, userData unsafe.Pointer) {
+	if setHandlerUserData(unsafe.Pointer(processor), userData) {
+		return
+	}
 	C.zbar_processor_set_userdata((*C.zbar_processor_t)(unsafe.Pointer(processor)), userData)
 }
 
@@ -678,6 +718,9 @@ func ZBarProcessorSetUserData(processor *ZBarProcessor, userData unsafe.Pointer)
  * @since 0.6
  */
 func ZBarProcessorGetUserData(processor *ZBarProcessor) unsafe.Pointer {
+	if userData, ok := handlerUserData(unsafe.Pointer(processor)); ok {
+		return userData
+	}
 	return C.zbar_processor_get_userdata((*C.zbar_processor_t)(unsafe.Pointer(processor)))
 }
 
@@ -688,6 +731,9 @@ func ZBarProcessorGetUserData(processor *ZBarProcessor) unsafe.Pointer {
  * @since 0.4
  */
 func ZBarProcessorSetConfig(processor *ZBarProcessor, symbology ZBarSymbolType, config ZBarConfig, value int) int {
+	if !configSupported(config) {
+		return 1
+	}
 	return int(C.zbar_processor_set_config((*C.zbar_processor_t)(unsafe.Pointer(processor)), C.zbar_symbol_type_t(symbology), C.zbar_config_t(config), C.int(value)))
 }
 
@@ -744,7 +790,7 @@ func ZBarProcessorSetActive(processor *ZBarProcessor, active int) int {
  * @since 0.10
  */
 func ZBarProcessorGetResults(processor *ZBarProcessor) *ZBarSymbolSet {
-	return (*ZBarSymbolSet)(C.zbar_processor_get_results((*C.zbar_processor_t)(unsafe.Pointer(processor))))
+	return (*ZBarSymbolSet)(unsafe.Pointer(C.zbar_processor_get_results((*C.zbar_processor_t)(unsafe.Pointer(processor)))))
 }
 
 /** wait for input to the display window from the user
@@ -785,17 +831,17 @@ func ZBarProcessImage(processor *ZBarProcessor, image *ZBarImage) int {
  * @returns a non-zero value suitable for passing to exit()
  */
 func ZBarProcessorErrorSpew(processor *ZBarProcessor, verbosity int) int {
-	return ZBarErrorSpew(processor, verbosity)
+	return ZBarErrorSpew(unsafe.Pointer(processor), verbosity)
 }
 
 /** retrieve the detail string for the last processor error. */
 func ZBarProcessorErrorString(processor *ZBarProcessor, verbosity int) string {
-	return ZBarErrorString(processor, verbosity)
+	return ZBarErrorString(unsafe.Pointer(processor), verbosity)
 }
 
 /** retrieve the type code for the last processor error. */
 func ZBarProcessorGetErrorCode(processor *ZBarProcessor) ZBarError {
-	return ZBarGetErrorCode(processor)
+	return ZBarGetErrorCode(unsafe.Pointer(processor))
 }
 
 /*@}*/
@@ -814,7 +860,7 @@ type ZBarVideo ZBarVideoStruct
 
 /** constructor. */
 func ZBarVideoCreate() *ZBarVideo {
-	return (*ZBarVideo)(C.zbar_video_create())
+	return (*ZBarVideo)(unsafe.Pointer(C.zbar_video_create()))
 }
 
 /** destructor. */
@@ -909,24 +955,24 @@ func ZBarVideoEnable(video *ZBarVideo, enable int) int {
  * @returns NULL if video is not enabled or an error occurs
  */
 func ZBarVideoNextImage(video *ZBarVideo) *ZBarImage {
-	return (*ZBarImage)(C.zbar_video_next_image((*C.zbar_video_t)(unsafe.Pointer(video))))
+	return (*ZBarImage)(unsafe.Pointer(C.zbar_video_next_image((*C.zbar_video_t)(unsafe.Pointer(video)))))
 }
 
 /** display detail for last video error to stderr.
  * @returns a non-zero value suitable for passing to exit()
  */
 func ZBarVideoErrorSpew(video *ZBarVideo, verbosity int) int {
-	return ZBarErrorSpew(video, verbosity)
+	return ZBarErrorSpew(unsafe.Pointer(video), verbosity)
 }
 
 /** retrieve the detail string for the last video error. */
 func ZBarVideoErrorString(video *ZBarVideo, verbosity int) string {
-	return ZBarErrorString(video, verbosity)
+	return ZBarErrorString(unsafe.Pointer(video), verbosity)
 }
 
 /** retrieve the type code for the last video error. */
 func ZBarVideoGetErrorCode(video *ZBarVideo) ZBarError {
-	return ZBarGetErrorCode(video)
+	return ZBarGetErrorCode(unsafe.Pointer(video))
 }
 
 /*@}*/
@@ -945,7 +991,7 @@ type ZBarWindow ZBarWindowStruct
 
 /** constructor. */
 func ZBarWindowCreate() *ZBarWindow {
-	return (*ZBarWindow)(C.zbar_window_create())
+	return (*ZBarWindow)(unsafe.Pointer(C.zbar_window_create()))
 }
 
 /** destructor. */
@@ -999,24 +1045,24 @@ func ZBarWindowRedraw(window *ZBarWindow) int {
  * @since 0.3, changed in 0.4 to not redraw window
  */
 func ZBarWindowResize(window *ZBarWindow, width, height uint32) int {
-	return int(C.zbar_window_redraw((*C.zbar_window_t)(unsafe.Pointer(window)), C.uint(width), C.uint(height)))
+	return int(C.zbar_window_resize((*C.zbar_window_t)(unsafe.Pointer(window)), C.uint(width), C.uint(height)))
 }
 
 /** display detail for last window error to stderr.
  * @returns a non-zero value suitable for passing to exit()
  */
 func ZBarWindowErrorSpew(window *ZBarWindow, verbosity int) int {
-	return ZBarErrorSpew(window, verbosity)
+	return ZBarErrorSpew(unsafe.Pointer(window), verbosity)
 }
 
 /** retrieve the detail string for the last window error. */
 func ZBarWindowErrorString(window *ZBarWindow, verbosity int) string {
-	return ZBarErrorString(window, verbosity)
+	return ZBarErrorString(unsafe.Pointer(window), verbosity)
 }
 
 /** retrieve the type code for the last window error. */
 func ZBarWindowGetErrorCode(window *ZBarWindow) ZBarError {
-	return ZBarGetErrorCode(window)
+	return ZBarGetErrorCode(unsafe.Pointer(window))
 }
 
 
@@ -1046,12 +1092,13 @@ type ZBarImageScanner ZBarImageScannerStruct
 
 /** constructor. */
 func ZBarImageScannerCreate() *ZBarImageScanner {
-	return (*ZBarImageScanner)(C.zbar_image_scanner_create())
+	return (*ZBarImageScanner)(unsafe.Pointer(C.zbar_image_scanner_create()))
 }
 
 /** destructor. */
 func ZBarImageScannerDestroy(scanner *ZBarImageScanner) {
 	C.zbar_image_scanner_destroy((*C.zbar_image_scanner_t)(unsafe.Pointer(scanner)))
+	forgetHandlers(unsafe.Pointer(scanner))
 }
 
 /** setup result handler callback.
@@ -1061,11 +1108,9 @@ func ZBarImageScannerDestroy(scanner *ZBarImageScanner) {
  * @returns the previously registered handler
  */
 func ZBarImageScannerSetDataHandler(scanner *ZBarImageScanner, handler ZBarImageDataHandler, userData unsafe.Pointer) ZBarImageDataHandler {
-	var fn = C.zbar_image_scanner_set_data_handler((*C.zbar_image_scanner_t)(unsafe.Pointer(scanner)), (*C.zbar_image_data_handler_t)(unsafe.Pointer(syscall.NewCallback(handler))), userData)
-	// TODO ...
-	return func(image *ZBarImage, userData unsafe.Pointer) {
-		fn(image, userData)
-	}
+	return setDataHandler(unsafe.Pointer(scanner), handler, userData, func(fn *C.zbar_image_data_handler_t, userData unsafe.Pointer) {
+		C.zbar_image_scanner_set_data_handler((*C.zbar_image_scanner_t)(unsafe.Pointer(scanner)), fn, userData)
+	})
 }
 
 
@@ -1076,6 +1121,9 @@ func ZBarImageScannerSetDataHandler(scanner *ZBarImageScanner, handler ZBarImage
  * @since 0.4
  */
 func ZBarImageScannerSetConfig(scanner *ZBarImageScanner, symbology ZBarSymbolType, config ZBarConfig, value int) int {
+	if !configSupported(config) {
+		return 1
+	}
 	return int(C.zbar_image_scanner_set_config((*C.zbar_image_scanner_t)(unsafe.Pointer(scanner)), C.zbar_symbol_type_t(symbology), C.zbar_config_t(config), C.int(value)))
 }
 
@@ -1127,7 +1175,7 @@ func ZBarImageScannerRecycleImage(scanner *ZBarImageScanner, image *ZBarImage) {
  * @since 0.10
  */
 func ZBarImageScannerGetResults(scanner *ZBarImageScanner) *ZBarSymbolSet {
-	return (*ZBarSymbolSet)(C.zbar_image_scanner_get_results((*C.zbar_image_scanner_t)(unsafe.Pointer(scanner))))
+	return (*ZBarSymbolSet)(unsafe.Pointer(C.zbar_image_scanner_get_results((*C.zbar_image_scanner_t)(unsafe.Pointer(scanner)))))
 }
 
 /** scan for symbols in provided image.  The image format must be
@@ -1162,12 +1210,13 @@ type ZBarDecoderHandler func(decoder *ZBarDecoder)
 
 /** constructor. */
 func ZBarDecoderCreate() *ZBarDecoder {
-	return (*ZBarDecoder)(C.zbar_decoder_create())
+	return (*ZBarDecoder)(unsafe.Pointer(C.zbar_decoder_create()))
 }
 
 /** destructor. */
 func ZBarDecoderDestroy(decoder *ZBarDecoder) {
 	C.zbar_decoder_destroy((*C.zbar_decoder_t)(unsafe.Pointer(decoder)))
+	forgetHandlers(unsafe.Pointer(decoder))
 }
 
 /** set config for indicated symbology (0 for all) to specified value.
@@ -1176,6 +1225,9 @@ func ZBarDecoderDestroy(decoder *ZBarDecoder) {
  * @since 0.4
  */
 func ZBarDecoderSetConfig(decoder *ZBarDecoder, symbology ZBarSymbolType, config ZBarConfig, value int) int {
+	if !configSupported(config) {
+		return 1
+	}
 	return int(C.zbar_decoder_set_config((*C.zbar_decoder_t)(unsafe.Pointer(decoder)), C.zbar_symbol_type_t(symbology), C.zbar_config_t(config), C.int(value)))
 }
 
@@ -1263,11 +1315,7 @@ func ZBarDecoderGetType(decoder *ZBarDecoder) ZBarSymbolType {
  * @returns the previously registered handler
  */
 func ZBarDecoderSetHandler(decoder *ZBarDecoder, handler ZBarDecoderHandler) ZBarDecoderHandler {
-	var fn = C.zbar_decoder_set_handler((*C.zbar_decoder_t)(unsafe.Pointer(decoder)), (*C.zbar_decoder_handler_t)(unsafe.Pointer(syscall.NewCallback(handler))))
-	// TODO...
-	return func(decoder *ZBarDecoder) {
-		fn((*C.zbar_decoder_t)(unsafe.Pointer(decoder)))
-	}
+	return setDecoderHandler(decoder, handler)
 }
 
 /** associate user specified data value with the decoder. */
@@ -1302,7 +1350,7 @@ type ZBarScanner ZBarScannerStruct
  * (so an initial BAR->SPACE transition may be discarded)
  */
 func ZBarScannerCreate(decoder *ZBarDecoder) *ZBarScanner {
-	return (*ZBarScanner)(C.zbar_scanner_create())
+	return (*ZBarScanner)(unsafe.Pointer(C.zbar_scanner_create((*C.zbar_decoder_t)(unsafe.Pointer(decoder)))))
 }
 
 /** destructor. */
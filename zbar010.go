@@ -0,0 +1,81 @@
+//go:build !zbar011
+
+package zbar
+
+/*------------------------------------------------------------*/
+/** @name libzbar 0.10 stand-ins
+ * Go implementations of functions added to the library after 0.10,
+ * used unless built with the zbar011 tag (see zbar011.go)
+ */
+/*@{*/
+
+/** libzbar 0.10 does not report symbol modifiers. */
+const haveModifiers = false
+
+/** retrieve general orientation of decoded symbol.
+ * libzbar 0.10 does not report orientation.
+ * @returns ZBAR_ORIENT_UNKNOWN
+ */
+func ZBarSymbolGetOrientation(symbol *ZBarSymbol) ZBarOrientation {
+	return ZBAR_ORIENT_UNKNOWN
+}
+
+/** retrieve a symbol's modifier flags.
+ * libzbar 0.10 does not report modifiers.
+ * @returns 0
+ */
+func ZBarSymbolGetModifiers(symbol *ZBarSymbol) uint32 {
+	return 0
+}
+
+/** retrieve a symbol's boolean configuration settings.
+ * libzbar 0.10 does not report configs.
+ * @returns 0
+ */
+func ZBarSymbolGetConfigs(symbol *ZBarSymbol) uint32 {
+	return 0
+}
+
+/** retrieve string name for configuration setting.
+ * @returns the name used by newer libraries or "" if the setting is
+ * unknown
+ */
+func ZBarGetConfigName(config ZBarConfig) string {
+	switch config {
+	case ZBAR_CFG_ENABLE:
+		return "ENABLE"
+	case ZBAR_CFG_ADD_CHECK:
+		return "ADD_CHECK"
+	case ZBAR_CFG_EMIT_CHECK:
+		return "EMIT_CHECK"
+	case ZBAR_CFG_ASCII:
+		return "ASCII"
+	case ZBAR_CFG_MIN_LEN:
+		return "MIN_LEN"
+	case ZBAR_CFG_MAX_LEN:
+		return "MAX_LEN"
+	case ZBAR_CFG_POSITION:
+		return "POSITION"
+	case ZBAR_CFG_X_DENSITY:
+		return "X_DENSITY"
+	case ZBAR_CFG_Y_DENSITY:
+		return "Y_DENSITY"
+	}
+	return ""
+}
+
+/** retrieve string name for modifier.
+ * @returns the name used by newer libraries or "" if the modifier is
+ * unknown
+ */
+func ZBarGetModifierName(modifier ZBarModifier) string {
+	switch modifier {
+	case ZBAR_MOD_GS1:
+		return "GS1"
+	case ZBAR_MOD_AIM:
+		return "AIM"
+	}
+	return ""
+}
+
+/*@}*/
@@ -0,0 +1,22 @@
+//go:build !zbar011
+
+package zbar
+
+import "testing"
+
+func TestZBar010Names(t *testing.T) {
+	if name := ZBarGetConfigName(ZBAR_CFG_X_DENSITY); name != "X_DENSITY" {
+		t.Errorf("config name = %q", name)
+	}
+	if name := ZBarGetConfigName(ZBAR_CFG_TEST_INVERTED); name != "" {
+		t.Errorf("unknown config name = %q", name)
+	}
+	if name := ZBarGetModifierName(ZBAR_MOD_AIM); name != "AIM" {
+		t.Errorf("modifier name = %q", name)
+	}
+
+	var sym = Symbol{Modifiers: 1 << uint(ZBAR_MOD_GS1)}
+	if !sym.HasModifier(ZBAR_MOD_GS1) || sym.HasModifier(ZBAR_MOD_AIM) {
+		t.Errorf("modifiers = %b", sym.Modifiers)
+	}
+}
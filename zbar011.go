@@ -0,0 +1,64 @@
+//go:build zbar011
+
+package zbar
+
+// #include <zbar.h>
+import "C"
+import "unsafe"
+
+/*------------------------------------------------------------*/
+/** @name libzbar 0.11 interface
+ * functions added to the library after 0.10, enabled by the zbar011
+ * build tag.  without the tag, zbar010.go provides stand-ins
+ */
+/*@{*/
+
+/** the library reports symbol modifiers. */
+const haveModifiers = true
+
+/** retrieve general orientation of decoded symbol.
+ * @returns a coarse, axis-aligned indication of symbol orientation or
+ * ZBAR_ORIENT_UNKNOWN if unknown
+ * @since 0.11
+ */
+func ZBarSymbolGetOrientation(symbol *ZBarSymbol) ZBarOrientation {
+	return ZBarOrientation(C.zbar_symbol_get_orientation((*C.zbar_symbol_t)(unsafe.Pointer(symbol))))
+}
+
+/** retrieve a symbol's modifier flags.
+ * @returns a bitmask indicating which modifiers were set for the
+ * symbol during decoding (1 << ZBarModifier)
+ * @since 0.11
+ */
+func ZBarSymbolGetModifiers(symbol *ZBarSymbol) uint32 {
+	return uint32(C.zbar_symbol_get_modifiers((*C.zbar_symbol_t)(unsafe.Pointer(symbol))))
+}
+
+/** retrieve a symbol's boolean configuration settings.
+ * @returns a bitmask of the boolean configs set when the symbol was
+ * decoded (1 << ZBarConfig)
+ * @since 0.11
+ */
+func ZBarSymbolGetConfigs(symbol *ZBarSymbol) uint32 {
+	return uint32(C.zbar_symbol_get_configs((*C.zbar_symbol_t)(unsafe.Pointer(symbol))))
+}
+
+/** retrieve string name for configuration setting.
+ * @returns the static string name for the specified config or
+ * "" if the setting is unknown
+ * @since 0.11
+ */
+func ZBarGetConfigName(config ZBarConfig) string {
+	return C.GoString(C.zbar_get_config_name(C.zbar_config_t(config)))
+}
+
+/** retrieve string name for modifier.
+ * @returns the static string name for the specified modifier or
+ * "" if the modifier is unknown
+ * @since 0.11
+ */
+func ZBarGetModifierName(modifier ZBarModifier) string {
+	return C.GoString(C.zbar_get_modifier_name(C.zbar_modifier_t(modifier)))
+}
+
+/*@}*/